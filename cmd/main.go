@@ -8,6 +8,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 
@@ -23,22 +24,67 @@ func main() {
 
 	logger.Setup(cfg)
 
-	dbpool, err := database.New(&cfg.ConfDB)
-	if err != nil {
-		slog.Error("failed to connect to database", "error", err)
-		os.Exit(1)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			slog.Error("migrate command failed", "error", err)
+			os.Exit(1)
+		}
+
+		return
 	}
-	defer dbpool.Close()
 
-	if err := database.Migrate(dbpool); err != nil {
-		slog.Error("failed to migrate database", "error", err)
+	repo, closeStore, err := newStore(cfg)
+	if err != nil {
 		os.Exit(1)
 	}
+	defer closeStore()
+
+	if cfg.Storage.TrashRetention > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-	repo := repository.NewTaskRepository(dbpool)
+		go repository.RunTrashPurge(ctx, repo, cfg.Storage.TrashRetention, cfg.Storage.TrashPurgeInterval)
+	}
 
 	if err := server.Setup(&cfg.Server, repo); err != nil {
 		slog.Error("server setup failed", "error", err)
 		os.Exit(1)
 	}
 }
+
+func newStore(cfg *config.Conf) (repository.Store, func(), error) {
+	slog.Info("initializing storage", "driver", cfg.Storage.Driver)
+
+	switch cfg.Storage.Driver {
+	case config.StorageDriverMemory:
+		return repository.NewMemoryTaskRepository(), func() {}, nil
+	case config.StorageDriverSQLite:
+		db, err := database.NewSQLite(&cfg.Storage)
+		if err != nil {
+			slog.Error("failed to open sqlite database", "error", err)
+			return nil, nil, err
+		}
+
+		if err := database.MigrateSQLite(db); err != nil {
+			slog.Error("failed to migrate sqlite database", "error", err)
+			db.Close()
+			return nil, nil, err
+		}
+
+		return repository.NewSQLiteTaskRepository(db), func() { db.Close() }, nil
+	}
+
+	dbpool, err := database.New(&cfg.ConfDB)
+	if err != nil {
+		slog.Error("failed to connect to database", "error", err)
+		return nil, nil, err
+	}
+
+	if err := database.Migrate(dbpool); err != nil {
+		slog.Error("failed to migrate database", "error", err)
+		dbpool.Close()
+		return nil, nil, err
+	}
+
+	return repository.NewTaskRepository(dbpool), dbpool.Close, nil
+}
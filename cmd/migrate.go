@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/config"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/database"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// runMigrate обрабатывает подкоманду migrate: up, down [steps], status
+func runMigrate(cfg *config.Conf, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, closeDB, err := newMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+
+		return migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+func newMigrator(cfg *config.Conf) (*database.Migrator, func(), error) {
+	switch cfg.Storage.Driver {
+	case config.StorageDriverSQLite:
+		db, err := database.NewSQLite(&cfg.Storage)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		m, err := database.NewSQLiteMigrator(db)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+
+		return m, func() { db.Close() }, nil
+	case config.StorageDriverPostgres:
+		dbpool, err := database.New(&cfg.ConfDB)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		m, err := database.NewPostgresMigrator(dbpool)
+		if err != nil {
+			dbpool.Close()
+			return nil, nil, err
+		}
+
+		return m, func() {
+			m.Close()
+			dbpool.Close()
+		}, nil
+	default:
+		slog.Warn("migrations are not supported by storage driver", "driver", cfg.Storage.Driver)
+		return nil, nil, fmt.Errorf("storage driver %q has no schema to migrate", cfg.Storage.Driver)
+	}
+}
@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/tasks": {
+        "/audit": {
             "get": {
-                "description": "Возвращает список всех задач",
+                "description": "Возвращает записи журнала аудита изменений задач от новых к старым. Каждая запись содержит значения\nизмененных полей задачи до и после действия, автора из заголовка X-Actor, идентификатор запроса\nиз X-Request-ID и IP адрес клиента. Заголовок X-Actor не проверяется сервером, поэтому автор -\nтолько указание клиента, которое стоит сверять с IP адресом и идентификатором запроса.\nКурсор следующей страницы возвращается в заголовке X-Next-Cursor",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,32 +25,138 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "audit"
+                ],
+                "summary": "Получить журнал аудита",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "task_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "delete",
+                            "restore",
+                            "purge"
+                        ],
+                        "type": "string",
+                        "description": "Действие",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменено не раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменено раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из заголовка X-Next-Cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получить список всех задач",
                 "responses": {
                     "200": {
-                        "description": "Список задач",
+                        "description": "Записи журнала аудита",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Task"
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы, если она есть"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Неверные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects": {
+            "get": {
+                "description": "Возвращает активные проекты, упорядоченные по названию, или архивные при archived=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получить список проектов",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть архивные проекты",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список проектов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Project"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Неверный параметр archived",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Создает новую задачу с указанными параметрами",
+                "description": "Создает проект с уникальным названием",
                 "consumes": [
                     "application/json"
                 ],
@@ -58,51 +164,105 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "projects"
                 ],
-                "summary": "Создать новую задачу",
+                "summary": "Создать проект",
                 "parameters": [
                     {
-                        "description": "Данные задачи (title, description, status)",
-                        "name": "task",
+                        "description": "Данные проекта (name, description)",
+                        "name": "project",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/tasks.taskRequest"
+                            "$ref": "#/definitions/projects.projectRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Созданная задача",
+                        "description": "Созданный проект",
                         "schema": {
-                            "$ref": "#/definitions/models.Task"
+                            "$ref": "#/definitions/models.Project"
                         }
                     },
                     "400": {
                         "description": "Неверный запрос",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Проект с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/{id}": {
+        "/projects/{id}": {
+            "get": {
+                "description": "Возвращает проект, в том числе архивный",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получить проект по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Проект",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Проект не найден",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
             "put": {
-                "description": "Обновляет существующую задачу по ID",
+                "description": "Заменяет название и описание проекта, отсутствующее описание очищается",
                 "consumes": [
                     "application/json"
                 ],
@@ -110,65 +270,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "projects"
                 ],
-                "summary": "Обновить задачу",
+                "summary": "Изменить проект",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID задачи",
+                        "description": "ID проекта",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Данные задачи (title, description, status)",
-                        "name": "task",
+                        "description": "Данные проекта (name, description)",
+                        "name": "project",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/tasks.taskRequest"
+                            "$ref": "#/definitions/projects.projectRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновленная задача",
+                        "description": "Обновленный проект",
                         "schema": {
-                            "$ref": "#/definitions/models.Task"
+                            "$ref": "#/definitions/models.Project"
                         }
                     },
                     "400": {
                         "description": "Неверный запрос",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "404": {
-                        "description": "Задача не найдена",
+                        "description": "Проект не найден",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Проект с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Удаляет задачу по указанному ID",
+                "description": "Удаляет проект. Параметр tasks определяет, что происходит с его задачами: restrict (по умолчанию)\nзапрещает удаление проекта с активными задачами, detach оставляет задачи вне проектов,\ntrash перемещает активные задачи в корзину. Задачи из корзины в любом режиме остаются без проекта",
                 "consumes": [
                     "application/json"
                 ],
@@ -176,97 +339,3410 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "projects"
                 ],
-                "summary": "Удалить задачу",
+                "summary": "Удалить проект",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID задачи",
+                        "description": "ID проекта",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "restrict",
+                            "detach",
+                            "trash"
+                        ],
+                        "type": "string",
+                        "description": "Действие с задачами проекта",
+                        "name": "tasks",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Задача успешно удалена"
+                        "description": "Проект удален"
                     },
                     "400": {
-                        "description": "Неверный ID",
+                        "description": "Неверный ID или параметр tasks",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "404": {
-                        "description": "Задача не найдена",
+                        "description": "Проект не найден",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "В проекте есть активные задачи",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Task": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "description": "Дата создания (только в ответе)\nexample: 2025-08-13T14:52:00Z",
-                    "type": "string"
-                },
-                "description": {
-                    "description": "Описание задачи\nrequired: false\nexample: Взять 2 литра и хлеб",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "ID задачи (только в ответе)\nexample: 1",
-                    "type": "integer"
-                },
-                "status": {
-                    "description": "Статус задачи\nrequired: true\nenum: new,in_progress,done\nexample: new",
-                    "type": "string"
-                },
-                "title": {
-                    "description": "Заголовок задачи\nrequired: true\nexample: Купить молоко",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "description": "Дата последнего обновления (только в ответе)\nexample: 2025-08-13T15:12:00Z",
-                    "type": "string"
+        },
+        "/projects/{id}/archive": {
+            "post": {
+                "description": "Перемещает проект в архив. Задачи проекта остаются доступны, но новые задачи\nнельзя создать в архивном проекте или переместить в него",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Архивировать проект",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Архивный проект",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Проект не найден",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
                 }
             }
         },
-        "tasks.taskRequest": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "Взять 2 литра и хлеб"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "new"
-                },
-                "title": {
-                    "type": "string",
-                    "example": "Купить молоко"
+        "/projects/{id}/tasks": {
+            "get": {
+                "description": "Возвращает страницу активных задач проекта. Поддерживает те же параметры фильтрации, сортировки\nи пагинации, что и GET /tasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получить задачи проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус задачи, можно указать несколько",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока заголовка или описания",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "low",
+                                "normal",
+                                "high",
+                                "urgent"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Приоритет задачи, можно указать несколько",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "due_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название метки, можно указать несколько",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в режиме курсора",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из заголовка X-Next-Cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы в постраничном режиме, начиная с 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в постраничном режиме",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список задач проекта",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Ссылки на соседние страницы"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее количество задач проекта"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID или параметры списка",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Проект не найден",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/unarchive": {
+            "post": {
+                "description": "Возвращает проект из архива",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Вернуть проект из архива",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Активный проект",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Проект не найден",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/statuses": {
+            "get": {
+                "description": "Возвращает статусы процесса, упорядоченные по позиции, вместе с допустимыми переходами из них",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statuses"
+                ],
+                "summary": "Получить список статусов",
+                "responses": {
+                    "200": {
+                        "description": "Список статусов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Status"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает статус с переходами из него в существующие статусы. Без position статус добавляется в конец процесса.\nПереходы в новый статус добавляются изменением других статусов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statuses"
+                ],
+                "summary": "Создать статус",
+                "parameters": [
+                    {
+                        "description": "Данные статуса (name, category, position, transitions)",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/statuses.statusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданный статус",
+                        "schema": {
+                            "$ref": "#/definitions/models.Status"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Статус с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/statuses/{name}": {
+            "put": {
+                "description": "Заменяет категорию, позицию и переходы статуса: без position позиция не меняется, отсутствующие transitions\nудаляют все переходы из статуса. Категорию встроенных статусов new и done изменить нельзя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statuses"
+                ],
+                "summary": "Изменить статус",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название статуса",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные статуса (category, position, transitions)",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/statuses.statusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Измененный статус",
+                        "schema": {
+                            "$ref": "#/definitions/models.Status"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Статус не найден",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Изменение категории встроенного статуса",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет статус вместе с переходами в него и из него. Нельзя удалить встроенные статусы new и done\nи статусы, в которых есть задачи, в том числе в корзине",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statuses"
+                ],
+                "summary": "Удалить статус",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название статуса",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Статус удален"
+                    },
+                    "400": {
+                        "description": "Неверное название",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Статус не найден",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Статус встроенный или в нем есть задачи",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Возвращает все метки, упорядоченные по названию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить список меток",
+                "responses": {
+                    "200": {
+                        "description": "Список меток",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает метку. Название приводится к нижнему регистру и должно быть уникальным",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Создать метку",
+                "parameters": [
+                    {
+                        "description": "Данные метки (name)",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tags.tagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданная метка",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Метка с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "put": {
+                "description": "Переименовывает метку. Задачи с этой меткой получают новую версию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Переименовать метку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID метки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные метки (name)",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tags.tagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Переименованная метка",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Метка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Метка с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет метку и открепляет ее от всех задач, в том числе удаленных",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удалить метку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID метки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Метка удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Метка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "description": "Возвращает страницу задач с учетом фильтров и сортировки (по умолчанию - по дате создания).\nПо умолчанию используется пагинация по курсору (limit, cursor); при указании page или per_page - постраничная.\nОбщее количество задач возвращается в заголовке X-Total-Count, курсор следующей страницы - в X-Next-Cursor,\nссылки на соседние страницы - в заголовке Link",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить список задач",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус задачи, можно указать несколько",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана не раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Обновлена не раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Обновлена раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "updated_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID первой задачи серии повторений: возвращает ее и все ее повторения",
+                        "name": "series_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока заголовка или описания",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "low",
+                                "normal",
+                                "high",
+                                "urgent"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Приоритет задачи, можно указать несколько",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "due_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название метки, можно указать несколько",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "-updated_at,title",
+                        "description": "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в режиме курсора",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из заголовка X-Next-Cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы в постраничном режиме, начиная с 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в постраничном режиме",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученной страницы",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список задач",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Слабый ETag страницы"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "Ссылки на соседние страницы"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее количество задач"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Страница не изменилась"
+                    },
+                    "400": {
+                        "description": "Неверные параметры фильтрации, сортировки или пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новую задачу с указанными параметрами. Задачу нельзя создать в архивном проекте.\nС parent_id задача создается как подзадача активной задачи, с recurrence - как первая задача серии повторений",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Создать новую задачу",
+                "parameters": [
+                    {
+                        "description": "Данные задачи (title, description, status, project_id, parent_id, priority, due_at, recurrence)",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.taskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданная задача",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Проект в архиве",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/batch": {
+            "post": {
+                "description": "Выполняет операции create, update и delete в одной транзакции и возвращает результат каждой операции\nс HTTP статусом. В режиме atomic первая ошибка отменяет весь пакет, остальные операции получают статус 424.\nВ режиме best_effort каждая операция применяется независимо. update изменяет только переданные поля.\nДанные задач проверяются так же, как в POST /tasks, ошибки полей возвращаются в результате операции со статусом 422",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Пакетное изменение задач",
+                "parameters": [
+                    {
+                        "description": "Режим и список операций",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.batchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Все операции выполнены",
+                        "schema": {
+                            "$ref": "#/definitions/tasks.batchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Часть операций в режиме best_effort завершилась ошибкой",
+                        "schema": {
+                            "$ref": "#/definitions/tasks.batchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Атомарный пакет отменен",
+                        "schema": {
+                            "$ref": "#/definitions/tasks.batchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/due": {
+            "get": {
+                "description": "Возвращает невыполненные задачи, срок которых наступит в течение within (по умолчанию 24h).\nПросроченные задачи не включаются, их возвращает GET /tasks/overdue. По умолчанию задачи упорядочены\nпо приоритету (сначала urgent), затем по сроку. Поддерживает те же параметры фильтрации,\nсортировки и пагинации, что и GET /tasks, границы due_after и due_before сужают представление",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить задачи с приближающимся сроком",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "48h",
+                        "description": "Период в формате Go duration (48h, 90m) или в днях (7d)",
+                        "name": "within",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "low",
+                                "normal",
+                                "high",
+                                "urgent"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Приоритет задачи, можно указать несколько",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не раньше (RFC 3339 или YYYY-MM-DD), сужает представление",
+                        "name": "due_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок раньше (RFC 3339 или YYYY-MM-DD), сужает представление",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название метки, можно указать несколько",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка, по умолчанию -priority,due_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в режиме курсора",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из заголовка X-Next-Cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы в постраничном режиме, начиная с 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в постраничном режиме",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задачи с приближающимся сроком",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Ссылки на соседние страницы"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее количество задач"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный параметр within или параметры списка",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/overdue": {
+            "get": {
+                "description": "Возвращает невыполненные задачи, срок которых уже наступил. По умолчанию задачи упорядочены\nпо приоритету (сначала urgent), затем по сроку. Поддерживает те же параметры фильтрации,\nсортировки и пагинации, что и GET /tasks, границы due_after и due_before сужают представление",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить просроченные задачи",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "low",
+                                "normal",
+                                "high",
+                                "urgent"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Приоритет задачи, можно указать несколько",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не раньше (RFC 3339 или YYYY-MM-DD), сужает представление",
+                        "name": "due_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок раньше (RFC 3339 или YYYY-MM-DD), сужает представление",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название метки, можно указать несколько",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка, по умолчанию -priority,due_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в режиме курсора",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из заголовка X-Next-Cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы в постраничном режиме, начиная с 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в постраничном режиме",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Просроченные задачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Ссылки на соседние страницы"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее количество просроченных задач"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры фильтрации, сортировки или пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/search": {
+            "get": {
+                "description": "Ищет задачи по заголовку и описанию, упорядочивая результаты по релевантности.\nЗапрос поддерживает фразы в кавычках, \"or\", исключение слов через \"-\" и префиксы вида \"прогр*\".\nНайденные слова в полях highlight выделены тегом \u003cmark\u003e. Доступно только для хранилища postgres",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Полнотекстовый поиск задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"купить молоко\" хлеб*",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы, начиная с 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные задачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskSearchResult"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Ссылки на соседние страницы"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее количество найденных задач"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Поиск не поддерживается хранилищем",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}": {
+            "get": {
+                "description": "Возвращает задачу по указанному ID вместе с активными задачами, которые ее блокируют и которые блокирует она",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить задачу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученной версии задачи",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskDetail"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Задача не изменилась"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Полностью заменяет задачу по ID: title обязателен, отсутствующее описание очищается,\nотсутствующий статус сбрасывается в new, приоритет - в normal, отсутствующий project_id убирает задачу\nиз проекта, отсутствующий parent_id делает задачу задачей верхнего уровня, отсутствующий due_at снимает срок,\nотсутствующий recurrence отключает повторение.\nЗадачу нельзя переместить в архивный проект или сделать подзадачей ее собственной подзадачи.\nСтатус меняется только по переходам, которые допускает процесс (GET /statuses).\nПеревод задачи в статус категории done выполняет все ее подзадачи, создает следующее повторение повторяющейся задачи и запрещен, пока задачу или ее подзадачи блокируют\nневыполненные задачи, если не передан ignore_blockers=true. Для частичного изменения используйте PATCH",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Заменить задачу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные задачи (title, description, status, project_id, parent_id, priority, due_at, recurrence)",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.taskRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Выполнить задачу без проверки блокирующих задач",
+                        "name": "ignore_blockers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag версии задачи, которую изменяет клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленная задача",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия задачи"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Проект в архиве, процесс не допускает переход в новый статус или задачу блокируют невыполненные задачи",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Задача была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибки проверки полей",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Перемещает задачу в корзину вместе со всеми подзадачами. Задачу можно восстановить\nчерез POST /tasks/{id}/restore, пока она не удалена из корзины вручную или по истечении срока хранения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Удалить задачу в корзину",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag версии задачи, которую удаляет клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Задача перемещена в корзину"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Задача была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Применяет к задаче JSON Merge Patch (RFC 7396, Content-Type application/merge-patch+json)\nили JSON Patch (RFC 6902, Content-Type application/json-patch+json).\nПатч применяется к документу {title, description, status, project_id, parent_id, priority, due_at, recurrence} в одной транзакции с чтением задачи.\nСтатус меняется только по переходам, которые допускает процесс (GET /statuses).\nПеревод задачи в статус категории done выполняет все ее подзадачи, создает следующее повторение повторяющейся задачи и запрещен, пока задачу или ее подзадачи блокируют\nневыполненные задачи, если не передан ignore_blockers=true",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Частично обновить задачу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge Patch документ или массив операций JSON Patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.patchDocument"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Выполнить задачу без проверки блокирующих задач",
+                        "name": "ignore_blockers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag версии задачи, которую изменяет клиент",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленная задача",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия задачи"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный патч",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Патч не применим к текущему состоянию задачи, проект в архиве, процесс не допускает переход в новый статус или задачу блокируют невыполненные задачи",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Задача была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый формат патча",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Результат патча не прошел проверку",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/blockers/{blocker_id}": {
+            "put": {
+                "description": "Делает задачу blocker_id блокирующей для задачи id: пока блокирующая задача не выполнена,\nзадачу id нельзя перевести в статус done. Повторное добавление не изменяет задачу и ее версию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Добавить блокирующую задачу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID блокирующей задачи",
+                        "name": "blocker_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача со связями блокировки",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskDetail"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача или блокирующая задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Связь образует цикл",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет связь блокировки между задачами. Если связи не было, задача и ее версия не изменяются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Удалить блокирующую задачу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID блокирующей задачи",
+                        "name": "blocker_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача со связями блокировки",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskDetail"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/history": {
+            "get": {
+                "description": "Возвращает создание задачи и все изменения ее статуса в порядке изменений, в том числе выполнение\nвместе с родительской задачей. Автор изменения передается в заголовке X-Actor запроса, который его выполнил",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить историю статусов задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История статусов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StatusChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/restore": {
+            "post": {
+                "description": "Возвращает удаленную задачу из корзины в список задач вместе с подзадачами, удаленными одновременно с ней.\nПодзадачу нельзя восстановить, пока ее родительская задача находится в корзине",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановить задачу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Восстановленная задача",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия задачи"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Родительская задача находится в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/subtasks": {
+            "get": {
+                "description": "Возвращает страницу активных подзадач первого уровня. Поддерживает те же параметры фильтрации,\nсортировки и пагинации, что и GET /tasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить подзадачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус задачи, можно указать несколько",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока заголовка или описания",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "low",
+                                "normal",
+                                "high",
+                                "urgent"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Приоритет задачи, можно указать несколько",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название метки, можно указать несколько",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в режиме курсора",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из заголовка X-Next-Cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы в постраничном режиме, начиная с 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в постраничном режиме",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подзадачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Ссылки на соседние страницы"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее количество подзадач"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID или параметры списка",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/tags/{name}": {
+            "put": {
+                "description": "Прикрепляет существующую метку к задаче. Повторное прикрепление не изменяет задачу и ее версию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Прикрепить метку к задаче",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название метки",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача с меткой",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID или название метки",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача или метка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Открепляет метку от задачи. Если метка не была прикреплена, задача и ее версия не изменяются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Открепить метку от задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название метки",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача без метки",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID или название метки",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача или метка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/tree": {
+            "get": {
+                "description": "Возвращает задачу и ее активные подзадачи всех уровней. Подзадачи каждого уровня упорядочены по дате создания",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить дерево задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Дерево задачи",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskTree"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "description": "Возвращает страницу удаленных задач. Поддерживает те же параметры фильтрации, сортировки\nи пагинации, что и GET /tasks. Задачи хранятся в корзине в течение TRASH_RETENTION",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Получить корзину",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус задачи, можно указать несколько",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока заголовка или описания",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "low",
+                                "normal",
+                                "high",
+                                "urgent"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Приоритет задачи, можно указать несколько",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "due_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок раньше (RFC 3339 или YYYY-MM-DD)",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название метки, можно указать несколько",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в режиме курсора",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из заголовка X-Next-Cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы в постраничном режиме, начиная с 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы в постраничном режиме",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список удаленных задач",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Ссылки на соседние страницы"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее количество задач в корзине"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры фильтрации, сортировки или пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{id}": {
+            "delete": {
+                "description": "Безвозвратно удаляет задачу, находящуюся в корзине, вместе со всеми подзадачами",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Удалить задачу из корзины",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Задача удалена безвозвратно"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Действие над задачей\nenum: create,update,delete,restore,purge\nexample: update",
+                    "type": "string"
+                },
+                "actor": {
+                    "description": "Автор изменения из заголовка X-Actor, указанный клиентом и не проверенный сервером, null если автор не указан\nexample: alice",
+                    "type": "string"
+                },
+                "after": {
+                    "description": "Значения измененных полей после действия, null для удаления задачи в корзину и из корзины\nexample: {\"status\":\"in_progress\",\"due_at\":\"2025-08-20T18:00:00Z\"}",
+                    "type": "object"
+                },
+                "at": {
+                    "description": "Время изменения\nexample: 2025-08-14T10:00:00Z",
+                    "type": "string"
+                },
+                "before": {
+                    "description": "Значения измененных полей до действия, null для создания и восстановления задачи\nexample: {\"status\":\"new\",\"due_at\":null}",
+                    "type": "object"
+                },
+                "id": {
+                    "description": "ID записи\nexample: 42",
+                    "type": "integer"
+                },
+                "ip": {
+                    "description": "IP адрес клиента\nexample: 203.0.113.7",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "Идентификатор запроса из заголовка X-Request-ID\nexample: 0b5c3f8e-6a1d-4d4e-9c1a-2f7b8e9d0a11",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "ID задачи, запись сохраняется и после удаления задачи из корзины\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Project": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "description": "Дата архивации, заполнена только у архивных проектов (только в ответе)\nexample: 2025-09-01T10:00:00Z",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Дата создания (только в ответе)\nexample: 2025-08-13T14:52:00Z",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Описание проекта\nrequired: false\nexample: Все, что нужно сделать до конца месяца",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID проекта (только в ответе)\nexample: 2",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Название проекта, уникально\nrequired: true\nexample: Переезд",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Дата последнего обновления (только в ответе)\nexample: 2025-08-13T15:12:00Z",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Status": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Категория статуса: todo, doing или done\nrequired: true\nenum: todo,doing,done\nexample: doing",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Название статуса, уникально\nrequired: true\nexample: review",
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Позиция статуса в процессе, статусы упорядочены по ней\nrequired: false\nexample: 3",
+                    "type": "integer"
+                },
+                "transitions": {
+                    "description": "Статусы, в которые можно перевести задачу из этого статуса\nrequired: false\nexample: [\"in_progress\",\"done\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.StatusChange": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Автор изменения из заголовка X-Actor, указанный клиентом и не проверенный сервером, null если автор не указан\nexample: alice",
+                    "type": "string"
+                },
+                "at": {
+                    "description": "Время изменения\nexample: 2025-08-14T10:00:00Z",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "Прежний статус, null для записи о создании задачи\nexample: new",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID записи\nexample: 15",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "ID задачи\nexample: 1",
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "Новый статус\nexample: in_progress",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubtaskProgress": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "description": "example: 2",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "example: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Дата создания (только в ответе)\nexample: 2025-08-13T14:52:00Z",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID метки (только в ответе)\nexample: 3",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Название метки в нижнем регистре, уникально\nrequired: true\nexample: urgent",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Task": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "Дата перехода в статус категории done, null для невыполненных задач (только в ответе)\nexample: 2025-08-15T17:30:00Z",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Дата создания (только в ответе)\nexample: 2025-08-13T14:52:00Z",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Дата перемещения в корзину, заполнена только у удаленных задач (только в ответе)\nexample: 2025-08-14T09:30:00Z",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Описание задачи\nrequired: false\nexample: Взять 2 литра и хлеб",
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "Срок выполнения задачи, null для задач без срока\nrequired: false\nexample: 2025-08-20T18:00:00Z",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID задачи (только в ответе)\nexample: 1",
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "description": "ID родительской задачи, null для задач верхнего уровня\nrequired: false\nexample: 7",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Приоритет задачи\nrequired: false\nenum: low,normal,high,urgent\nexample: high",
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "ID проекта задачи, null для задач вне проектов\nrequired: false\nexample: 2",
+                    "type": "integer"
+                },
+                "recurrence": {
+                    "description": "Правило повторения в формате RRULE, null для неповторяющихся задач\nrequired: false\nexample: FREQ=WEEKLY;BYDAY=MO",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "ID первой задачи серии повторений, null для первой задачи и неповторяющихся задач (только в ответе)\nexample: 12",
+                    "type": "integer"
+                },
+                "spawned_from": {
+                    "description": "ID задачи серии, выполнение которой создало это повторение, null для задач, созданных вручную (только в ответе)\nexample: 15",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "Дата первого перехода в статус категории doing или done, null для задач, работа над которыми не начиналась (только в ответе)\nexample: 2025-08-14T10:00:00Z",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус задачи из настроенного процесса, см. Status\nrequired: true\nexample: new",
+                    "type": "string"
+                },
+                "subtasks": {
+                    "description": "Количество выполненных и всех активных подзадач первого уровня (только в ответе)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SubtaskProgress"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Названия меток задачи в алфавитном порядке (только в ответе)\nexample: [\"bug\",\"urgent\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Заголовок задачи\nrequired: true\nexample: Купить молоко",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Дата последнего обновления (только в ответе)\nexample: 2025-08-13T15:12:00Z",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Версия задачи, увеличивается при каждом изменении и передается в заголовке ETag (только в ответе)\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TaskDetail": {
+            "type": "object",
+            "properties": {
+                "blocked_by": {
+                    "description": "Активные задачи, которые блокируют задачу",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskRef"
+                    }
+                },
+                "blocks": {
+                    "description": "Активные задачи, которые блокирует задача",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskRef"
+                    }
+                },
+                "completed_at": {
+                    "description": "Дата перехода в статус категории done, null для невыполненных задач (только в ответе)\nexample: 2025-08-15T17:30:00Z",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Дата создания (только в ответе)\nexample: 2025-08-13T14:52:00Z",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Дата перемещения в корзину, заполнена только у удаленных задач (только в ответе)\nexample: 2025-08-14T09:30:00Z",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Описание задачи\nrequired: false\nexample: Взять 2 литра и хлеб",
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "Срок выполнения задачи, null для задач без срока\nrequired: false\nexample: 2025-08-20T18:00:00Z",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID задачи (только в ответе)\nexample: 1",
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "description": "ID родительской задачи, null для задач верхнего уровня\nrequired: false\nexample: 7",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Приоритет задачи\nrequired: false\nenum: low,normal,high,urgent\nexample: high",
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "ID проекта задачи, null для задач вне проектов\nrequired: false\nexample: 2",
+                    "type": "integer"
+                },
+                "recurrence": {
+                    "description": "Правило повторения в формате RRULE, null для неповторяющихся задач\nrequired: false\nexample: FREQ=WEEKLY;BYDAY=MO",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "ID первой задачи серии повторений, null для первой задачи и неповторяющихся задач (только в ответе)\nexample: 12",
+                    "type": "integer"
+                },
+                "spawned_from": {
+                    "description": "ID задачи серии, выполнение которой создало это повторение, null для задач, созданных вручную (только в ответе)\nexample: 15",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "Дата первого перехода в статус категории doing или done, null для задач, работа над которыми не начиналась (только в ответе)\nexample: 2025-08-14T10:00:00Z",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус задачи из настроенного процесса, см. Status\nrequired: true\nexample: new",
+                    "type": "string"
+                },
+                "subtasks": {
+                    "description": "Количество выполненных и всех активных подзадач первого уровня (только в ответе)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SubtaskProgress"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Названия меток задачи в алфавитном порядке (только в ответе)\nexample: [\"bug\",\"urgent\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Заголовок задачи\nrequired: true\nexample: Купить молоко",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Дата последнего обновления (только в ответе)\nexample: 2025-08-13T15:12:00Z",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Версия задачи, увеличивается при каждом изменении и передается в заголовке ETag (только в ответе)\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TaskHighlight": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "example: Взять 2 литра и хлеб",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "example: Купить \u003cmark\u003eмолоко\u003c/mark\u003e",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TaskRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "example: 4",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "example: in_progress",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "example: Согласовать дату переезда",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TaskSearchResult": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "Дата перехода в статус категории done, null для невыполненных задач (только в ответе)\nexample: 2025-08-15T17:30:00Z",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Дата создания (только в ответе)\nexample: 2025-08-13T14:52:00Z",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Дата перемещения в корзину, заполнена только у удаленных задач (только в ответе)\nexample: 2025-08-14T09:30:00Z",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Описание задачи\nrequired: false\nexample: Взять 2 литра и хлеб",
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "Срок выполнения задачи, null для задач без срока\nrequired: false\nexample: 2025-08-20T18:00:00Z",
+                    "type": "string"
+                },
+                "highlight": {
+                    "description": "Фрагменты заголовка и описания с найденными словами, выделенными тегом \u003cmark\u003e",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaskHighlight"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "ID задачи (только в ответе)\nexample: 1",
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "description": "ID родительской задачи, null для задач верхнего уровня\nrequired: false\nexample: 7",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Приоритет задачи\nrequired: false\nenum: low,normal,high,urgent\nexample: high",
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "ID проекта задачи, null для задач вне проектов\nrequired: false\nexample: 2",
+                    "type": "integer"
+                },
+                "rank": {
+                    "description": "Релевантность задачи запросу, чем больше, тем выше\nexample: 0.6",
+                    "type": "number"
+                },
+                "recurrence": {
+                    "description": "Правило повторения в формате RRULE, null для неповторяющихся задач\nrequired: false\nexample: FREQ=WEEKLY;BYDAY=MO",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "ID первой задачи серии повторений, null для первой задачи и неповторяющихся задач (только в ответе)\nexample: 12",
+                    "type": "integer"
+                },
+                "spawned_from": {
+                    "description": "ID задачи серии, выполнение которой создало это повторение, null для задач, созданных вручную (только в ответе)\nexample: 15",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "Дата первого перехода в статус категории doing или done, null для задач, работа над которыми не начиналась (только в ответе)\nexample: 2025-08-14T10:00:00Z",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус задачи из настроенного процесса, см. Status\nrequired: true\nexample: new",
+                    "type": "string"
+                },
+                "subtasks": {
+                    "description": "Количество выполненных и всех активных подзадач первого уровня (только в ответе)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SubtaskProgress"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Названия меток задачи в алфавитном порядке (только в ответе)\nexample: [\"bug\",\"urgent\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Заголовок задачи\nrequired: true\nexample: Купить молоко",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Дата последнего обновления (только в ответе)\nexample: 2025-08-13T15:12:00Z",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Версия задачи, увеличивается при каждом изменении и передается в заголовке ETag (только в ответе)\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TaskTree": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "Подзадачи в порядке создания",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskTree"
+                    }
+                },
+                "completed_at": {
+                    "description": "Дата перехода в статус категории done, null для невыполненных задач (только в ответе)\nexample: 2025-08-15T17:30:00Z",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Дата создания (только в ответе)\nexample: 2025-08-13T14:52:00Z",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Дата перемещения в корзину, заполнена только у удаленных задач (только в ответе)\nexample: 2025-08-14T09:30:00Z",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Описание задачи\nrequired: false\nexample: Взять 2 литра и хлеб",
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "Срок выполнения задачи, null для задач без срока\nrequired: false\nexample: 2025-08-20T18:00:00Z",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID задачи (только в ответе)\nexample: 1",
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "description": "ID родительской задачи, null для задач верхнего уровня\nrequired: false\nexample: 7",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Приоритет задачи\nrequired: false\nenum: low,normal,high,urgent\nexample: high",
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "ID проекта задачи, null для задач вне проектов\nrequired: false\nexample: 2",
+                    "type": "integer"
+                },
+                "recurrence": {
+                    "description": "Правило повторения в формате RRULE, null для неповторяющихся задач\nrequired: false\nexample: FREQ=WEEKLY;BYDAY=MO",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "ID первой задачи серии повторений, null для первой задачи и неповторяющихся задач (только в ответе)\nexample: 12",
+                    "type": "integer"
+                },
+                "spawned_from": {
+                    "description": "ID задачи серии, выполнение которой создало это повторение, null для задач, созданных вручную (только в ответе)\nexample: 15",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "Дата первого перехода в статус категории doing или done, null для задач, работа над которыми не начиналась (только в ответе)\nexample: 2025-08-14T10:00:00Z",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус задачи из настроенного процесса, см. Status\nrequired: true\nexample: new",
+                    "type": "string"
+                },
+                "subtasks": {
+                    "description": "Количество выполненных и всех активных подзадач первого уровня (только в ответе)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SubtaskProgress"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Названия меток задачи в алфавитном порядке (только в ответе)\nexample: [\"bug\",\"urgent\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Заголовок задачи\nrequired: true\nexample: Купить молоко",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Дата последнего обновления (только в ответе)\nexample: 2025-08-13T15:12:00Z",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Версия задачи, увеличивается при каждом изменении и передается в заголовке ETag (только в ответе)\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "problem.Code": {
+            "type": "string",
+            "enum": [
+                "invalid_request",
+                "invalid_body",
+                "invalid_id",
+                "invalid_parameter",
+                "invalid_patch",
+                "validation_failed",
+                "constraint_violation",
+                "task_not_found",
+                "project_not_found",
+                "tag_not_found",
+                "status_not_found",
+                "route_not_found",
+                "method_not_allowed",
+                "patch_conflict",
+                "conflict",
+                "project_archived",
+                "project_not_empty",
+                "parent_deleted",
+                "dependency_cycle",
+                "task_blocked",
+                "invalid_transition",
+                "status_in_use",
+                "status_builtin",
+                "version_mismatch",
+                "payload_too_large",
+                "unsupported_media_type",
+                "batch_aborted",
+                "internal_error",
+                "not_implemented"
+            ],
+            "x-enum-varnames": [
+                "CodeInvalidRequest",
+                "CodeInvalidBody",
+                "CodeInvalidID",
+                "CodeInvalidParameter",
+                "CodeInvalidPatch",
+                "CodeValidationFailed",
+                "CodeConstraintViolation",
+                "CodeTaskNotFound",
+                "CodeProjectNotFound",
+                "CodeTagNotFound",
+                "CodeStatusNotFound",
+                "CodeRouteNotFound",
+                "CodeMethodNotAllowed",
+                "CodePatchConflict",
+                "CodeConflict",
+                "CodeProjectArchived",
+                "CodeProjectNotEmpty",
+                "CodeParentDeleted",
+                "CodeDependencyCycle",
+                "CodeTaskBlocked",
+                "CodeInvalidTransition",
+                "CodeStatusInUse",
+                "CodeStatusBuiltin",
+                "CodeVersionMismatch",
+                "CodePayloadTooLarge",
+                "CodeUnsupportedMediaType",
+                "CodeBatchAborted",
+                "CodeInternal",
+                "CodeNotImplemented"
+            ]
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/problem.Code"
+                        }
+                    ],
+                    "example": "task_not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "task not found"
+                },
+                "errors": {
+                    "description": "Ошибки отдельных полей для validation_failed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/tasks/42"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:rest-todo-list:problem:task_not_found"
+                }
+            }
+        },
+        "projects.projectRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Все, что нужно сделать до конца месяца"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Переезд"
+                }
+            }
+        },
+        "statuses.statusRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "todo",
+                        "doing",
+                        "done"
+                    ],
+                    "example": "doing"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "review"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "in_progress",
+                        "done"
+                    ]
+                }
+            }
+        },
+        "tags.tagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "urgent"
+                }
+            }
+        },
+        "tasks.batchOperation": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID задачи для update и delete",
+                    "type": "integer",
+                    "example": 1
+                },
+                "ignore_blockers": {
+                    "description": "Перевести задачу в done без проверки блокирующих задач, только для update",
+                    "type": "boolean",
+                    "example": false
+                },
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "example": "update"
+                },
+                "task": {
+                    "description": "Данные задачи: все поля для create, изменяемые поля для update",
+                    "type": "object"
+                },
+                "version": {
+                    "description": "Ожидаемая версия задачи для update и delete, аналог If-Match",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "tasks.batchRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "Режим выполнения: atomic (по умолчанию) отменяет весь пакет при первой ошибке,\nbest_effort применяет каждую операцию независимо",
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ],
+                    "example": "atomic"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tasks.batchOperation"
+                    }
+                }
+            }
+        },
+        "tasks.batchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "atomic"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tasks.batchResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "tasks.batchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Ошибка операции в формате RFC 7807",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    ]
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "op": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "task": {
+                    "$ref": "#/definitions/models.Task"
+                }
+            }
+        },
+        "tasks.patchDocument": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Взять 2 литра и хлеб"
+                },
+                "due_at": {
+                    "type": "string",
+                    "example": "2025-08-20T18:00:00Z"
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "high"
+                },
+                "project_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "in_progress"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Купить молоко"
+                }
+            }
+        },
+        "tasks.taskRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Взять 2 литра и хлеб"
+                },
+                "due_at": {
+                    "description": "Срок выполнения в формате RFC 3339, null - задача без срока",
+                    "type": "string",
+                    "example": "2025-08-20T18:00:00Z"
+                },
+                "parent_id": {
+                    "description": "ID родительской задачи, null - задача верхнего уровня",
+                    "type": "integer",
+                    "example": 7
+                },
+                "priority": {
+                    "description": "Приоритет: low, normal, high, urgent",
+                    "type": "string",
+                    "example": "high"
+                },
+                "project_id": {
+                    "description": "ID проекта, null - задача вне проектов",
+                    "type": "integer",
+                    "example": 2
+                },
+                "recurrence": {
+                    "description": "Правило повторения RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, UNTIL или COUNT; null - задача не повторяется",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "new"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Купить молоко"
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "required"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "title"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "title is required"
                 }
             }
         }
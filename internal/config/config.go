@@ -1,16 +1,24 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/joeshaw/envdecode"
 )
 
+const (
+	StorageDriverPostgres = "postgres"
+	StorageDriverMemory   = "memory"
+	StorageDriverSQLite   = "sqlite"
+)
+
 type Conf struct {
-	Server ConfServer
-	ConfDB ConfDB
-	Env    string `env:"ENV,default=dev"`
+	Server  ConfServer
+	Storage ConfStorage
+	ConfDB  ConfDB
+	Env     string `env:"ENV,default=dev"`
 }
 
 type ConfServer struct {
@@ -19,14 +27,29 @@ type ConfServer struct {
 	TimeoutRead  time.Duration `env:"SERVER_TIMEOUT_READ,required"`
 	TimeoutWrite time.Duration `env:"SERVER_TIMEOUT_WRITE,required"`
 	TimeoutIdle  time.Duration `env:"SERVER_TIMEOUT_IDLE,required"`
+
+	PageSizeDefault int `env:"SERVER_PAGE_SIZE_DEFAULT,default=20"`
+	PageSizeMax     int `env:"SERVER_PAGE_SIZE_MAX,default=100"`
+
+	BatchMaxSize int `env:"SERVER_BATCH_MAX_SIZE,default=1000"`
+}
+
+type ConfStorage struct {
+	Driver     string `env:"STORAGE_DRIVER,default=postgres"`
+	SQLitePath string `env:"SQLITE_PATH,default=todo.db"`
+
+	// TrashRetention срок хранения задач в корзине, 0 отключает автоматическую очистку
+	TrashRetention     time.Duration `env:"TRASH_RETENTION,default=720h"`
+	TrashPurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL,default=1h"`
 }
 
+// ConfDB обязателен только для драйвера postgres, поэтому проверяется в validate
 type ConfDB struct {
-	Host     string `env:"DB_HOST,required"`
-	Port     int    `env:"DB_PORT,required"`
-	User     string `env:"DB_USER,required"`
-	Password string `env:"DB_PASSWORD,required"`
-	Name     string `env:"DB_NAME,required"`
+	Host     string `env:"DB_HOST"`
+	Port     int    `env:"DB_PORT"`
+	User     string `env:"DB_USER"`
+	Password string `env:"DB_PASSWORD"`
+	Name     string `env:"DB_NAME"`
 }
 
 func New() *Conf {
@@ -36,5 +59,54 @@ func New() *Conf {
 		panic(err)
 	}
 
+	if err := c.validate(); err != nil {
+		slog.Error("invalid configuration", "error", err)
+		panic(err)
+	}
+
 	return &c
 }
+
+func (c *Conf) validate() error {
+	if c.Server.PageSizeDefault <= 0 || c.Server.PageSizeMax < c.Server.PageSizeDefault {
+		return fmt.Errorf("page size limits must satisfy 0 < SERVER_PAGE_SIZE_DEFAULT <= SERVER_PAGE_SIZE_MAX")
+	}
+
+	if c.Server.BatchMaxSize <= 0 {
+		return fmt.Errorf("SERVER_BATCH_MAX_SIZE must be positive")
+	}
+
+	if c.Storage.TrashRetention < 0 || c.Storage.TrashPurgeInterval <= 0 {
+		return fmt.Errorf("TRASH_RETENTION must not be negative and TRASH_PURGE_INTERVAL must be positive")
+	}
+
+	switch c.Storage.Driver {
+	case StorageDriverPostgres:
+		return c.ConfDB.validate()
+	case StorageDriverMemory, StorageDriverSQLite:
+		return nil
+	default:
+		return fmt.Errorf("unknown storage driver %q", c.Storage.Driver)
+	}
+}
+
+func (c *ConfDB) validate() error {
+	var missing string
+
+	switch {
+	case c.Host == "":
+		missing = "DB_HOST"
+	case c.Port == 0:
+		missing = "DB_PORT"
+	case c.User == "":
+		missing = "DB_USER"
+	case c.Password == "":
+		missing = "DB_PASSWORD"
+	case c.Name == "":
+		missing = "DB_NAME"
+	default:
+		return nil
+	}
+
+	return fmt.Errorf("the environment variable \"%s\" is missing", missing)
+}
@@ -2,29 +2,421 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
+// migrationLockID ключ advisory lock, под которым реплики по очереди применяют миграции
+const migrationLockID = 7_301_245_118
+
+//go:embed migrations
+var migrationsFS embed.FS
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type dialect struct {
+	name          string
+	dir           string
+	createTable   string
+	insertVersion string
+	deleteVersion string
+	lock          string
+	unlock        string
+	// foreignKeysOff и foreignKeysOn отключают проверку внешних ключей на время миграций,
+	// checkForeignKeys возвращает нарушения внешних ключей, найденные перед фиксацией миграции
+	foreignKeysOff   string
+	foreignKeysOn    string
+	checkForeignKeys string
+}
+
+var postgresDialect = dialect{
+	name: "postgres",
+	dir:  "migrations/postgres",
+	createTable: `
+  CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT now()
+  );`,
+	insertVersion: `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+	deleteVersion: `DELETE FROM schema_migrations WHERE version = $1`,
+	lock:          fmt.Sprintf(`SELECT pg_advisory_lock(%d)`, migrationLockID),
+	unlock:        fmt.Sprintf(`SELECT pg_advisory_unlock(%d)`, migrationLockID),
+}
+
+// SQLite допускает только одного писателя, поэтому отдельная блокировка не нужна. Внешние ключи
+// на время миграций отключаются, как рекомендует документация SQLite для изменения схемы: с ними нельзя
+// добавить колонку со ссылкой и значением по умолчанию, а пересоздание таблицы удаляет связанные строки
+var sqliteDialect = dialect{
+	name: "sqlite",
+	dir:  "migrations/sqlite",
+	createTable: `
+  CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );`,
+	insertVersion:    `INSERT INTO schema_migrations (version, name) VALUES (?, ?)`,
+	deleteVersion:    `DELETE FROM schema_migrations WHERE version = ?`,
+	foreignKeysOff:   `PRAGMA foreign_keys = OFF`,
+	foreignKeysOn:    `PRAGMA foreign_keys = ON`,
+	checkForeignKeys: `PRAGMA foreign_key_check`,
+}
+
+// Migrator применяет и откатывает версионированные миграции схемы,
+// отслеживая примененные версии в таблице schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+	// ownsDB означает, что db открыт самим Migrator и закрывается в Close
+	ownsDB bool
+}
+
+// NewPostgresMigrator открывает поверх пула отдельный *sql.DB, который закрывает Close. Сам пул остается открытым
+func NewPostgresMigrator(dbpool *pgxpool.Pool) (*Migrator, error) {
+	db := stdlib.OpenDBFromPool(dbpool)
+
+	m, err := newMigrator(db, postgresDialect)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	m.ownsDB = true
+
+	return m, nil
+}
+
+func NewSQLiteMigrator(db *sql.DB) (*Migrator, error) {
+	return newMigrator(db, sqliteDialect)
+}
+
+func newMigrator(db *sql.DB, d dialect) (*Migrator, error) {
+	migrations, err := loadMigrations(d.dir)
+	if err != nil {
+		slog.Error("failed to load migrations", "dialect", d.name, "error", err)
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: d, migrations: migrations}, nil
+}
+
+// Close освобождает ресурсы Migrator. База данных, переданная в NewSQLiteMigrator, остается открытой
+func (m *Migrator) Close() error {
+	if !m.ownsDB {
+		return nil
+	}
+
+	return m.db.Close()
+}
+
 func Migrate(dbpool *pgxpool.Pool) error {
-	slog.Info("starting database migration")
+	m, err := NewPostgresMigrator(dbpool)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
 
-	query := `
-  CREATE TABLE IF NOT EXISTS tasks (
-    id SERIAL PRIMARY KEY,
-    title TEXT NOT NULL,
-    description TEXT,
-    status TEXT CHECK (status IN ('new', 'in_progress', 'done')) DEFAULT 'new',
-    created_at TIMESTAMP DEFAULT now(),
-    updated_at TIMESTAMP DEFAULT now()
-  );`
+	return m.Up(context.Background())
+}
 
-	if _, err := dbpool.Exec(context.Background(), query); err != nil {
-		slog.Error("error while creating the database", "error", err)
+func MigrateSQLite(db *sql.DB) error {
+	m, err := NewSQLiteMigrator(db)
+	if err != nil {
 		return err
 	}
 
-	slog.Info("migration completed successfully", "table", "tasks")
+	return m.Up(context.Background())
+}
+
+// Up применяет все еще не примененные миграции по возрастанию версии
+func (m *Migrator) Up(ctx context.Context) error {
+	slog.Info("starting database migration", "dialect", m.dialect.name)
+
+	applied := 0
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := versions[mig.Version]; ok {
+				continue
+			}
+
+			slog.Info("applying migration", "version", mig.Version, "name", mig.Name)
+
+			if err := m.apply(ctx, conn, mig.Up, m.dialect.insertVersion, mig.Version, mig.Name); err != nil {
+				slog.Error("migration failed", "version", mig.Version, "name", mig.Name, "error", err)
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			applied++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("migration completed successfully", "applied", applied)
 	return nil
 }
+
+// Down откатывает steps последних примененных миграций
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	slog.Info("starting database rollback", "dialect", m.dialect.name, "steps", steps)
+
+	byVersion := make(map[int]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		applied := make([]int, 0, len(versions))
+		for v := range versions {
+			applied = append(applied, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(applied)))
+
+		for i := 0; i < steps && i < len(applied); i++ {
+			mig, ok := byVersion[applied[i]]
+			if !ok {
+				return fmt.Errorf("migration %d is applied but missing from the binary", applied[i])
+			}
+
+			slog.Info("rolling back migration", "version", mig.Version, "name", mig.Name)
+
+			if err := m.apply(ctx, conn, mig.Down, m.dialect.deleteVersion, mig.Version); err != nil {
+				slog.Error("rollback failed", "version", mig.Version, "name", mig.Name, "error", err)
+				return fmt.Errorf("rollback %d_%s: %w", mig.Version, mig.Name, err)
+			}
+		}
+
+		slog.Info("rollback completed successfully")
+		return nil
+	})
+}
+
+// Status возвращает все известные миграции с отметкой о времени применения
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			s := MigrationStatus{Migration: mig}
+			if at, ok := versions[mig.Version]; ok {
+				s.AppliedAt = &at
+			}
+			statuses = append(statuses, s)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		slog.Error("failed to acquire migration connection", "error", err)
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect.lock != "" {
+		if _, err := conn.ExecContext(ctx, m.dialect.lock); err != nil {
+			slog.Error("failed to acquire migration lock", "error", err)
+			return err
+		}
+
+		defer func() {
+			if _, err := conn.ExecContext(context.Background(), m.dialect.unlock); err != nil {
+				slog.Error("failed to release migration lock", "error", err)
+			}
+		}()
+	}
+
+	if m.dialect.foreignKeysOff != "" {
+		// PRAGMA foreign_keys не действует внутри транзакции, поэтому переключается для всего соединения
+		if _, err := conn.ExecContext(ctx, m.dialect.foreignKeysOff); err != nil {
+			slog.Error("failed to disable foreign keys", "error", err)
+			return err
+		}
+
+		defer func() {
+			if _, err := conn.ExecContext(context.Background(), m.dialect.foreignKeysOn); err != nil {
+				slog.Error("failed to enable foreign keys", "error", err)
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		slog.Error("failed to create schema_migrations table", "error", err)
+		return err
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		slog.Error("failed to read applied migrations", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[int]time.Time{}
+	for rows.Next() {
+		var (
+			v  int
+			at time.Time
+		)
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, err
+		}
+		versions[v] = at
+	}
+
+	return versions, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script, bookkeeping string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return err
+	}
+
+	if m.dialect.checkForeignKeys != "" {
+		if err := checkForeignKeys(ctx, tx, m.dialect.checkForeignKeys); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// checkForeignKeys возвращает ошибку, если миграция оставила строки со ссылками на несуществующие строки
+func checkForeignKeys(ctx context.Context, tx *sql.Tx, query string) error {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var (
+			table  string
+			rowID  sql.NullInt64
+			parent string
+			fkID   int
+		)
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("foreign key violation: %s row %d references missing %s row", table, rowID.Int64, parent)
+	}
+
+	return rows.Err()
+}
+
+func loadMigrations(dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		match := migrationFileRe.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file name %q", e.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		body, err := migrationsFS.ReadFile(path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, mig.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down scripts", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	if len(migrations) == 0 {
+		return nil, errors.New("no migrations found")
+	}
+
+	return migrations, nil
+}
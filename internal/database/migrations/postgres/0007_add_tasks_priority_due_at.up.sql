@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+  ADD COLUMN priority TEXT NOT NULL DEFAULT 'normal' CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+  ADD COLUMN due_at TIMESTAMP;
+
+CREATE INDEX tasks_due_at_idx ON tasks (due_at) WHERE deleted_at IS NULL AND due_at IS NOT NULL;
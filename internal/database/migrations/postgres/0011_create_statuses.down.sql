@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS tasks_status_idx;
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_fkey;
+
+UPDATE tasks
+SET status = CASE statuses.category WHEN 'done' THEN 'done' WHEN 'doing' THEN 'in_progress' ELSE 'new' END
+FROM statuses
+WHERE statuses.name = tasks.status AND tasks.status NOT IN ('new', 'in_progress', 'done');
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_check CHECK (status IN ('new', 'in_progress', 'done'));
+
+DROP TABLE IF EXISTS status_transitions;
+
+DROP TABLE IF EXISTS statuses;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS statuses (
+  name TEXT PRIMARY KEY,
+  category TEXT NOT NULL CHECK (category IN ('todo', 'doing', 'done')),
+  position INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status_transitions (
+  from_status TEXT NOT NULL REFERENCES statuses (name) ON DELETE CASCADE,
+  to_status TEXT NOT NULL REFERENCES statuses (name) ON DELETE CASCADE,
+  PRIMARY KEY (from_status, to_status),
+  CHECK (from_status <> to_status)
+);
+
+INSERT INTO statuses (name, category, position) VALUES
+  ('new', 'todo', 1),
+  ('in_progress', 'doing', 2),
+  ('done', 'done', 3);
+
+INSERT INTO status_transitions (from_status, to_status)
+SELECT f.name, t.name FROM statuses f CROSS JOIN statuses t WHERE f.name <> t.name;
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check;
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_fkey FOREIGN KEY (status) REFERENCES statuses (name);
+
+CREATE INDEX tasks_status_idx ON tasks (status);
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+  DROP COLUMN IF EXISTS completed_at,
+  DROP COLUMN IF EXISTS started_at;
+
+DROP TABLE IF EXISTS task_status_history;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS task_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+  from_status TEXT,
+  to_status TEXT NOT NULL,
+  changed_at TIMESTAMP NOT NULL DEFAULT now(),
+  actor TEXT
+);
+
+CREATE INDEX task_status_history_task_id_idx ON task_status_history (task_id, id);
+
+ALTER TABLE tasks
+  ADD COLUMN started_at TIMESTAMP,
+  ADD COLUMN completed_at TIMESTAMP;
+
+-- время переходов до появления истории неизвестно, поэтому для начатых и выполненных задач берется время их последнего изменения
+UPDATE tasks
+SET started_at = CASE WHEN status IN (SELECT name FROM statuses WHERE category <> 'todo') THEN updated_at END,
+  completed_at = CASE WHEN status IN (SELECT name FROM statuses WHERE category = 'done') THEN updated_at END;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS task_audit;
+
+DROP FUNCTION IF EXISTS task_audit_append_only();
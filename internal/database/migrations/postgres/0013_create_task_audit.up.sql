@@ -0,0 +1,28 @@
+-- task_id не ссылается на tasks: записи аудита переживают безвозвратное удаление задачи
+CREATE TABLE IF NOT EXISTS task_audit (
+  id BIGSERIAL PRIMARY KEY,
+  task_id INTEGER NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+  before JSONB,
+  after JSONB,
+  actor TEXT,
+  request_id TEXT,
+  ip TEXT,
+  changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX task_audit_task_id_idx ON task_audit (task_id, id);
+
+CREATE INDEX task_audit_actor_idx ON task_audit (actor, id) WHERE actor IS NOT NULL;
+
+CREATE INDEX task_audit_changed_at_idx ON task_audit (changed_at);
+
+CREATE OR REPLACE FUNCTION task_audit_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'task_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_audit_append_only
+BEFORE UPDATE OR DELETE ON task_audit
+FOR EACH ROW EXECUTE FUNCTION task_audit_append_only();
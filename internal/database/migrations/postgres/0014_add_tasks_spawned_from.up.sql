@@ -0,0 +1,5 @@
+-- spawned_from задача, выполнение которой создало повторение. Уникальность не дает создать
+-- второе повторение, когда ту же задачу возвращают в работу и выполняют снова
+ALTER TABLE tasks ADD COLUMN spawned_from INTEGER;
+
+CREATE UNIQUE INDEX tasks_spawned_from_key ON tasks (spawned_from);
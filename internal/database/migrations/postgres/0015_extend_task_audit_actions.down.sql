@@ -0,0 +1,6 @@
+ALTER TABLE task_audit DROP CONSTRAINT IF EXISTS task_audit_action_check;
+
+-- журнал не изменяется, поэтому записи восстановления и безвозвратного удаления сохраняются,
+-- а прежнее ограничение проверяется только для новых записей
+ALTER TABLE task_audit ADD CONSTRAINT task_audit_action_check
+  CHECK (action IN ('create', 'update', 'delete')) NOT VALID;
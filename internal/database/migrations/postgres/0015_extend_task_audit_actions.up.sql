@@ -0,0 +1,4 @@
+ALTER TABLE task_audit DROP CONSTRAINT IF EXISTS task_audit_action_check;
+
+ALTER TABLE task_audit ADD CONSTRAINT task_audit_action_check
+  CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge'));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_due_at_idx;
+
+ALTER TABLE tasks DROP COLUMN due_at;
+
+ALTER TABLE tasks DROP COLUMN priority;
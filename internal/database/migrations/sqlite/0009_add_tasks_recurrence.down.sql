@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_series_id_idx;
+
+ALTER TABLE tasks DROP COLUMN series_id;
+
+ALTER TABLE tasks DROP COLUMN recurrence;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks ADD COLUMN recurrence TEXT;
+
+ALTER TABLE tasks ADD COLUMN series_id INTEGER;
+
+CREATE INDEX tasks_series_id_idx ON tasks (series_id);
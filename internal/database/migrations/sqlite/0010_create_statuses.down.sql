@@ -0,0 +1,20 @@
+ALTER TABLE tasks ADD COLUMN fixed_status TEXT CHECK (fixed_status IN ('new', 'in_progress', 'done')) DEFAULT 'new';
+
+UPDATE tasks
+SET fixed_status = CASE
+  WHEN status IN ('new', 'in_progress', 'done') THEN status
+  ELSE (
+    SELECT CASE category WHEN 'done' THEN 'done' WHEN 'doing' THEN 'in_progress' ELSE 'new' END
+    FROM statuses WHERE statuses.name = tasks.status
+  )
+END;
+
+DROP INDEX IF EXISTS tasks_status_idx;
+
+ALTER TABLE tasks DROP COLUMN status;
+
+ALTER TABLE tasks RENAME COLUMN fixed_status TO status;
+
+DROP TABLE IF EXISTS status_transitions;
+
+DROP TABLE IF EXISTS statuses;
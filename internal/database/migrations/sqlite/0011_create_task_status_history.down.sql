@@ -0,0 +1,5 @@
+ALTER TABLE tasks DROP COLUMN completed_at;
+
+ALTER TABLE tasks DROP COLUMN started_at;
+
+DROP TABLE IF EXISTS task_status_history;
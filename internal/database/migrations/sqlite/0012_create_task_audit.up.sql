@@ -0,0 +1,30 @@
+-- task_id не ссылается на tasks: записи аудита переживают безвозвратное удаление задачи
+CREATE TABLE IF NOT EXISTS task_audit (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  task_id INTEGER NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+  before TEXT,
+  after TEXT,
+  actor TEXT,
+  request_id TEXT,
+  ip TEXT,
+  changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX task_audit_task_id_idx ON task_audit (task_id, id);
+
+CREATE INDEX task_audit_actor_idx ON task_audit (actor, id) WHERE actor IS NOT NULL;
+
+CREATE INDEX task_audit_changed_at_idx ON task_audit (changed_at);
+
+CREATE TRIGGER task_audit_no_update
+BEFORE UPDATE ON task_audit
+BEGIN
+  SELECT RAISE(ABORT, 'task_audit is append-only');
+END;
+
+CREATE TRIGGER task_audit_no_delete
+BEFORE DELETE ON task_audit
+BEGIN
+  SELECT RAISE(ABORT, 'task_audit is append-only');
+END;
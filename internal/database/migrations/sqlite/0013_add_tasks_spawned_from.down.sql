@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tasks_spawned_from_key;
+
+ALTER TABLE tasks DROP COLUMN spawned_from;
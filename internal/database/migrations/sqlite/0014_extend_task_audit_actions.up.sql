@@ -0,0 +1,37 @@
+-- SQLite не умеет изменять CHECK ограничение, поэтому журнал переносится в новую таблицу
+CREATE TABLE task_audit_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  task_id INTEGER NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge')),
+  before TEXT,
+  after TEXT,
+  actor TEXT,
+  request_id TEXT,
+  ip TEXT,
+  changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO task_audit_new (id, task_id, action, before, after, actor, request_id, ip, changed_at)
+SELECT id, task_id, action, before, after, actor, request_id, ip, changed_at FROM task_audit;
+
+DROP TABLE task_audit;
+
+ALTER TABLE task_audit_new RENAME TO task_audit;
+
+CREATE INDEX task_audit_task_id_idx ON task_audit (task_id, id);
+
+CREATE INDEX task_audit_actor_idx ON task_audit (actor, id) WHERE actor IS NOT NULL;
+
+CREATE INDEX task_audit_changed_at_idx ON task_audit (changed_at);
+
+CREATE TRIGGER task_audit_no_update
+BEFORE UPDATE ON task_audit
+BEGIN
+  SELECT RAISE(ABORT, 'task_audit is append-only');
+END;
+
+CREATE TRIGGER task_audit_no_delete
+BEFORE DELETE ON task_audit
+BEGIN
+  SELECT RAISE(ABORT, 'task_audit is append-only');
+END;
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/config"
+	_ "modernc.org/sqlite"
+)
+
+const fmtSQLiteDSN = "file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+func NewSQLite(cfg *config.ConfStorage) (*sql.DB, error) {
+	slog.Info("opening sqlite database", "path", cfg.SQLitePath)
+
+	db, err := sql.Open("sqlite", fmt.Sprintf(fmtSQLiteDSN, cfg.SQLitePath))
+	if err != nil {
+		slog.Error("unable to open sqlite database", "error", err)
+		return nil, err
+	}
+
+	// SQLite допускает только одного писателя, поэтому все запросы идут через одно соединение
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(context.Background()); err != nil {
+		slog.Error("error making a test ping to the sqlite database", "error", err)
+		db.Close()
+		return nil, err
+	}
+
+	slog.Info("sqlite database opened successfully")
+	return db, nil
+}
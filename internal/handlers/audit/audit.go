@@ -0,0 +1,146 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/config"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+	"github.com/gofiber/fiber/v2"
+)
+
+const headerNextCursor = "X-Next-Cursor"
+
+type Handler struct {
+	repo repository.AuditStore
+	cfg  *config.ConfServer
+}
+
+func NewHandler(repo repository.AuditStore, cfg *config.ConfServer) *Handler {
+	return &Handler{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// List возвращает страницу журнала аудита
+// @Summary Получить журнал аудита
+// @Description Возвращает записи журнала аудита изменений задач от новых к старым. Каждая запись содержит значения
+// @Description измененных полей задачи до и после действия, автора из заголовка X-Actor, идентификатор запроса
+// @Description из X-Request-ID и IP адрес клиента. Заголовок X-Actor не проверяется сервером, поэтому автор -
+// @Description только указание клиента, которое стоит сверять с IP адресом и идентификатором запроса.
+// @Description Курсор следующей страницы возвращается в заголовке X-Next-Cursor
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param task_id query int false "ID задачи"
+// @Param actor query string false "Автор изменения"
+// @Param action query string false "Действие" Enums(create, update, delete, restore, purge)
+// @Param from query string false "Изменено не раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param to query string false "Изменено раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param limit query int false "Размер страницы"
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor предыдущей страницы"
+// @Success 200 {array} models.AuditEntry "Записи журнала аудита"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы, если она есть"
+// @Failure 400 {object} problem.Problem "Неверные параметры запроса"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /audit [get]
+func (h *Handler) List(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling get audit log request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	filter, err := h.parseFilter(c)
+	if err != nil {
+		slog.Warn("invalid audit log parameters", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, err.Error())
+	}
+
+	limit := filter.Limit
+	// на одну запись больше лимита, чтобы определить наличие следующей страницы
+	filter.Limit++
+
+	entries, err := h.repo.Audit(ctx, filter)
+	if err != nil {
+		slog.Error("failed to get audit log", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to get audit log")
+	}
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+		c.Set(headerNextCursor, strconv.Itoa(entries[limit-1].ID))
+	}
+
+	slog.Info("audit log retrieved successfully", "count", len(entries), "ip", c.IP())
+
+	return c.JSON(entries)
+}
+
+func (h *Handler) parseFilter(c *fiber.Ctx) (repository.AuditFilter, error) {
+	f := repository.AuditFilter{}
+
+	limit, err := params.Int(c, "limit", h.cfg.PageSizeDefault, h.cfg.PageSizeMax)
+	if err != nil {
+		return f, err
+	}
+	f.Limit = limit
+
+	if c.Query("task_id") != "" {
+		id, err := params.Int(c, "task_id", 0, math.MaxInt32)
+		if err != nil {
+			return f, err
+		}
+		f.TaskID = &id
+	}
+
+	if c.Query("cursor") != "" {
+		if f.BeforeID, err = params.Int(c, "cursor", 0, math.MaxInt); err != nil {
+			return f, errors.New("cursor must be an entry ID from X-Next-Cursor")
+		}
+	}
+
+	f.Actor = strings.TrimSpace(c.Query("actor"))
+	if len([]rune(f.Actor)) > requestinfo.MaxActorLength {
+		return f, fmt.Errorf("actor must be at most %d characters", requestinfo.MaxActorLength)
+	}
+
+	f.Action = c.Query("action")
+	if f.Action != "" && !slices.Contains(models.AuditActions, f.Action) {
+		return f, fmt.Errorf("action must be one of %s", strings.Join(models.AuditActions, ", "))
+	}
+
+	bounds := []struct {
+		key string
+		dst **time.Time
+	}{
+		{"from", &f.From},
+		{"to", &f.To},
+	}
+
+	for _, b := range bounds {
+		raw := c.Query(b.key)
+		if raw == "" {
+			continue
+		}
+
+		t, err := params.Time(raw)
+		if err != nil {
+			return f, fmt.Errorf("%s must be a RFC 3339 timestamp or a YYYY-MM-DD date", b.key)
+		}
+		*b.dst = &t
+	}
+
+	return f, nil
+}
@@ -0,0 +1,48 @@
+// Package params разбирает параметры пути и запроса, общие для обработчиков
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ID возвращает положительный идентификатор из параметра пути id
+func ID(c *fiber.Ctx) (int, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return 0, problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid id")
+	}
+
+	return id, nil
+}
+
+// DateLayout формат даты без времени в параметрах запроса
+const DateLayout = "2006-01-02"
+
+// Int возвращает целое число от 1 до maxValue из параметра запроса key или def, если параметр не задан
+func Int(c *fiber.Ctx, key string, def, maxValue int) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return def, nil
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 || v > maxValue {
+		return 0, fmt.Errorf("%s must be an integer between 1 and %d", key, maxValue)
+	}
+
+	return v, nil
+}
+
+// Time разбирает метку времени в формате RFC 3339 или дату в формате DateLayout
+func Time(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(DateLayout, s)
+}
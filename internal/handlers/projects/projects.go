@@ -0,0 +1,341 @@
+package projects
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxNameLength        = 100
+	maxDescriptionLength = 5000
+)
+
+type Handler struct {
+	repo repository.ProjectStore
+}
+
+// projectRequest схема тела запроса с данными проекта для документации, разбор выполняет decodeProject
+type projectRequest struct {
+	Name        string  `json:"name" example:"Переезд"`
+	Description *string `json:"description,omitempty" example:"Все, что нужно сделать до конца месяца"`
+}
+
+func NewHandler(repo repository.ProjectStore) *Handler {
+	return &Handler{repo: repo}
+}
+
+// List возвращает список проектов
+// @Summary Получить список проектов
+// @Description Возвращает активные проекты, упорядоченные по названию, или архивные при archived=true
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param archived query bool false "Вернуть архивные проекты"
+// @Success 200 {array} models.Project "Список проектов"
+// @Failure 400 {object} problem.Problem "Неверный параметр archived"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects [get]
+func (h *Handler) List(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling list projects request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	archived := false
+	if raw := c.Query("archived"); raw != "" {
+		var err error
+		if archived, err = strconv.ParseBool(raw); err != nil {
+			slog.Warn("invalid archived parameter", "archived", raw, "ip", c.IP())
+			return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, "archived must be true or false")
+		}
+	}
+
+	projects, err := h.repo.ListProjects(ctx, archived)
+	if err != nil {
+		slog.Error("failed to list projects", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to list projects")
+	}
+
+	slog.Info("projects listed successfully", "count", len(projects), "archived", archived, "ip", c.IP())
+
+	return c.JSON(projects)
+}
+
+// Get возвращает проект по ID
+// @Summary Получить проект по ID
+// @Description Возвращает проект, в том числе архивный
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Success 200 {object} models.Project "Проект"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Проект не найден"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects/{id} [get]
+func (h *Handler) Get(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling get project request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid project ID in get request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	p, err := h.repo.GetProject(ctx, id)
+	if err != nil {
+		return storeError(c, id, "get", err)
+	}
+
+	return c.JSON(p)
+}
+
+// Create создает новый проект
+// @Summary Создать проект
+// @Description Создает проект с уникальным названием
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param project body projectRequest true "Данные проекта (name, description)"
+// @Success 200 {object} models.Project "Созданный проект"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 409 {object} problem.Problem "Проект с таким названием уже существует"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects [post]
+func (h *Handler) Create(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling create project request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	p, err := decodeProject(c.Body())
+	if err != nil {
+		slog.Warn("project creation rejected", "error", err, "ip", c.IP())
+		return problem.Payload(err)
+	}
+
+	slog.Info("creating project", "name", p.Name, "ip", c.IP())
+
+	if err := h.repo.CreateProject(ctx, p); err != nil {
+		return storeError(c, 0, "create", err)
+	}
+
+	slog.Info("project created successfully", "id", p.ID, "name", p.Name, "ip", c.IP())
+
+	return c.JSON(p)
+}
+
+// Update заменяет название и описание проекта
+// @Summary Изменить проект
+// @Description Заменяет название и описание проекта, отсутствующее описание очищается
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param project body projectRequest true "Данные проекта (name, description)"
+// @Success 200 {object} models.Project "Обновленный проект"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 404 {object} problem.Problem "Проект не найден"
+// @Failure 409 {object} problem.Problem "Проект с таким названием уже существует"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects/{id} [put]
+func (h *Handler) Update(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling update project request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid project ID in update request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	req, err := decodeProject(c.Body())
+	if err != nil {
+		slog.Warn("project update rejected", "error", err, "project_id", id, "ip", c.IP())
+		return problem.Payload(err)
+	}
+
+	p, err := h.repo.UpdateProject(ctx, id, req.Name, req.Description)
+	if err != nil {
+		return storeError(c, id, "update", err)
+	}
+
+	slog.Info("project updated successfully", "id", id, "ip", c.IP())
+
+	return c.JSON(p)
+}
+
+// Archive архивирует проект
+// @Summary Архивировать проект
+// @Description Перемещает проект в архив. Задачи проекта остаются доступны, но новые задачи
+// @Description нельзя создать в архивном проекте или переместить в него
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Success 200 {object} models.Project "Архивный проект"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Проект не найден"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects/{id}/archive [post]
+func (h *Handler) Archive(c *fiber.Ctx) error {
+	return h.setArchived(c, true)
+}
+
+// Unarchive возвращает проект из архива
+// @Summary Вернуть проект из архива
+// @Description Возвращает проект из архива
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Success 200 {object} models.Project "Активный проект"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Проект не найден"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects/{id}/unarchive [post]
+func (h *Handler) Unarchive(c *fiber.Ctx) error {
+	return h.setArchived(c, false)
+}
+
+func (h *Handler) setArchived(c *fiber.Ctx, archived bool) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling archive project request", "archived", archived, "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid project ID in archive request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	p, err := h.repo.SetProjectArchived(ctx, id, archived)
+	if err != nil {
+		return storeError(c, id, "archive", err)
+	}
+
+	slog.Info("project archive state changed", "id", id, "archived", archived, "ip", c.IP())
+
+	return c.JSON(p)
+}
+
+// Delete удаляет проект
+// @Summary Удалить проект
+// @Description Удаляет проект. Параметр tasks определяет, что происходит с его задачами: restrict (по умолчанию)
+// @Description запрещает удаление проекта с активными задачами, detach оставляет задачи вне проектов,
+// @Description trash перемещает активные задачи в корзину. Задачи из корзины в любом режиме остаются без проекта
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param tasks query string false "Действие с задачами проекта" Enums(restrict, detach, trash)
+// @Success 204 "Проект удален"
+// @Failure 400 {object} problem.Problem "Неверный ID или параметр tasks"
+// @Failure 404 {object} problem.Problem "Проект не найден"
+// @Failure 409 {object} problem.Problem "В проекте есть активные задачи"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects/{id} [delete]
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling delete project request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid project ID in delete request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	mode := repository.ProjectDeleteMode(c.Query("tasks", string(repository.ProjectDeleteRestrict)))
+	switch mode {
+	case repository.ProjectDeleteRestrict, repository.ProjectDeleteDetach, repository.ProjectDeleteTrash:
+	default:
+		slog.Warn("invalid project delete mode", "tasks", mode, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, "tasks must be restrict, detach or trash")
+	}
+
+	slog.Info("deleting project", "id", id, "tasks", mode, "ip", c.IP())
+
+	if err := h.repo.DeleteProject(ctx, id, mode); err != nil {
+		return storeError(c, id, "delete", err)
+	}
+
+	slog.Info("project deleted successfully", "id", id, "ip", c.IP())
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// decodeProject разбирает и проверяет данные проекта
+func decodeProject(body []byte) (*models.Project, error) {
+	var (
+		name        string
+		description *string
+	)
+
+	v := &validation.Validator{}
+
+	if _, err := v.DecodeObject(body, map[string]any{
+		"name":        &name,
+		"description": &description,
+	}); err != nil {
+		return nil, err
+	}
+
+	v.Required("name", name)
+	v.MaxLength("name", name, maxNameLength)
+
+	p := &models.Project{Name: name}
+	if description != nil {
+		v.MaxLength("description", *description, maxDescriptionLength)
+		p.Description = *description
+	}
+
+	return p, v.Err()
+}
+
+// storeError логирует ошибку хранилища при выполнении action над проектом и возвращает ответ на нее.
+// Неизвестные ошибки скрываются за 500
+func storeError(c *fiber.Ctx, id int, action string, err error) error {
+	var p *problem.Problem
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		p = problem.New(fiber.StatusNotFound, problem.CodeProjectNotFound, "project not found")
+	case errors.Is(err, repository.ErrProjectNotEmpty):
+		p = problem.New(fiber.StatusConflict, problem.CodeProjectNotEmpty, "project has active tasks, use tasks=detach or tasks=trash")
+	case errors.Is(err, repository.ErrConflict):
+		p = problem.New(fiber.StatusConflict, problem.CodeConflict, "project with this name already exists")
+	case errors.Is(err, repository.ErrInvalid):
+		p = problem.New(fiber.StatusUnprocessableEntity, problem.CodeConstraintViolation, "project violates storage constraints")
+	default:
+		slog.Error("failed to "+action+" project", "error", err, "project_id", id, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to "+action+" project")
+	}
+
+	slog.Warn(action+" project rejected", "error", err, "code", p.Code, "project_id", id, "ip", c.IP())
+
+	return p
+}
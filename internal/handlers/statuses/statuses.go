@@ -0,0 +1,268 @@
+package statuses
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+const nameRule = "be a status name of lowercase latin letters, digits and '_'"
+
+type Handler struct {
+	repo repository.StatusStore
+}
+
+// statusRequest схема тела запроса с данными статуса для документации, разбор выполняет decodeStatus
+type statusRequest struct {
+	Name        string   `json:"name" example:"review"`
+	Category    string   `json:"category" example:"doing" enums:"todo,doing,done"`
+	Position    int      `json:"position" example:"3"`
+	Transitions []string `json:"transitions" example:"in_progress,done"`
+}
+
+func NewHandler(repo repository.StatusStore) *Handler {
+	return &Handler{repo: repo}
+}
+
+// List возвращает процесс работы над задачами
+// @Summary Получить список статусов
+// @Description Возвращает статусы процесса, упорядоченные по позиции, вместе с допустимыми переходами из них
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Status "Список статусов"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /statuses [get]
+func (h *Handler) List(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling list statuses request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	statuses, err := h.repo.ListStatuses(ctx)
+	if err != nil {
+		slog.Error("failed to list statuses", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to list statuses")
+	}
+
+	slog.Info("statuses listed successfully", "count", len(statuses), "ip", c.IP())
+
+	return c.JSON(statuses)
+}
+
+// Create создает новый статус
+// @Summary Создать статус
+// @Description Создает статус с переходами из него в существующие статусы. Без position статус добавляется в конец процесса.
+// @Description Переходы в новый статус добавляются изменением других статусов
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Param status body statusRequest true "Данные статуса (name, category, position, transitions)"
+// @Success 200 {object} models.Status "Созданный статус"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 409 {object} problem.Problem "Статус с таким названием уже существует"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /statuses [post]
+func (h *Handler) Create(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling create status request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	s, err := decodeStatus(c.Body(), "")
+	if err != nil {
+		slog.Warn("status creation rejected", "error", err, "ip", c.IP())
+		return problem.Payload(err)
+	}
+
+	if err := h.repo.CreateStatus(ctx, s); err != nil {
+		return storeError(c, s.Name, "create", err)
+	}
+
+	slog.Info("status created successfully", "name", s.Name, "category", s.Category, "ip", c.IP())
+
+	return c.JSON(s)
+}
+
+// Update изменяет статус
+// @Summary Изменить статус
+// @Description Заменяет категорию, позицию и переходы статуса: без position позиция не меняется, отсутствующие transitions
+// @Description удаляют все переходы из статуса. Категорию встроенных статусов new и done изменить нельзя
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Param name path string true "Название статуса"
+// @Param status body statusRequest true "Данные статуса (category, position, transitions)"
+// @Success 200 {object} models.Status "Измененный статус"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 404 {object} problem.Problem "Статус не найден"
+// @Failure 409 {object} problem.Problem "Изменение категории встроенного статуса"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /statuses/{name} [put]
+func (h *Handler) Update(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling update status request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	name, err := parseName(c)
+	if err != nil {
+		slog.Warn("invalid status name in update request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	s, err := decodeStatus(c.Body(), name)
+	if err != nil {
+		slog.Warn("status update rejected", "error", err, "status", name, "ip", c.IP())
+		return problem.Payload(err)
+	}
+
+	if err := h.repo.UpdateStatus(ctx, s); err != nil {
+		return storeError(c, name, "update", err)
+	}
+
+	slog.Info("status updated successfully", "name", name, "category", s.Category, "ip", c.IP())
+
+	return c.JSON(s)
+}
+
+// Delete удаляет статус
+// @Summary Удалить статус
+// @Description Удаляет статус вместе с переходами в него и из него. Нельзя удалить встроенные статусы new и done
+// @Description и статусы, в которых есть задачи, в том числе в корзине
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Param name path string true "Название статуса"
+// @Success 204 "Статус удален"
+// @Failure 400 {object} problem.Problem "Неверное название"
+// @Failure 404 {object} problem.Problem "Статус не найден"
+// @Failure 409 {object} problem.Problem "Статус встроенный или в нем есть задачи"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /statuses/{name} [delete]
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling delete status request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	name, err := parseName(c)
+	if err != nil {
+		slog.Warn("invalid status name in delete request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	if err := h.repo.DeleteStatus(ctx, name); err != nil {
+		return storeError(c, name, "delete", err)
+	}
+
+	slog.Info("status deleted successfully", "name", name, "ip", c.IP())
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// decodeStatus разбирает и проверяет данные статуса. Для существующего статуса name берется из пути,
+// иначе из тела запроса. Повторяющиеся переходы отбрасываются
+func decodeStatus(body []byte, name string) (*models.Status, error) {
+	s := &models.Status{Name: name}
+	v := &validation.Validator{}
+
+	fields := map[string]any{
+		"category":    &s.Category,
+		"position":    &s.Position,
+		"transitions": &s.Transitions,
+	}
+	if name == "" {
+		fields["name"] = &s.Name
+	}
+
+	if _, err := v.DecodeObject(body, fields); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		s.Name = strings.TrimSpace(s.Name)
+
+		v.Required("name", s.Name)
+		v.MaxLength("name", s.Name, models.MaxStatusNameLength)
+		if s.Name != "" {
+			v.Matches("name", s.Name, models.StatusNamePattern, nameRule)
+		}
+	}
+
+	if !v.Failed("category") {
+		v.OneOf("category", s.Category, models.StatusCategories)
+	}
+
+	if s.Position < 0 {
+		v.Add("position", validation.CodeOutOfRange, "position must be a positive integer")
+	}
+
+	transitions := []string{}
+	for _, to := range s.Transitions {
+		switch {
+		case !models.StatusNamePattern.MatchString(to) || len(to) > models.MaxStatusNameLength:
+			v.Matches("transitions", to, models.StatusNamePattern, nameRule)
+		case to == s.Name:
+			v.Add("transitions", validation.CodeCycle, "transitions must not contain the status itself")
+		case !slices.Contains(transitions, to):
+			transitions = append(transitions, to)
+		}
+	}
+	s.Transitions = transitions
+
+	return s, v.Err()
+}
+
+// storeError логирует ошибку хранилища при выполнении action над статусом и возвращает ответ на нее.
+// Неизвестные ошибки скрываются за 500
+func storeError(c *fiber.Ctx, name, action string, err error) error {
+	var p *problem.Problem
+
+	switch {
+	case errors.Is(err, repository.ErrStatusNotFound):
+		p = problem.Validation(validation.Errors{
+			{Field: "transitions", Code: validation.CodeNotFound, Message: "transition status not found"},
+		})
+	case errors.Is(err, repository.ErrStatusInUse):
+		p = problem.New(fiber.StatusConflict, problem.CodeStatusInUse, "status is used by tasks, move them to another status first")
+	case errors.Is(err, repository.ErrStatusBuiltin):
+		p = problem.New(fiber.StatusConflict, problem.CodeStatusBuiltin, "built-in status can not be deleted or moved to another category")
+	case errors.Is(err, repository.ErrNotFound):
+		p = problem.New(fiber.StatusNotFound, problem.CodeStatusNotFound, "status not found")
+	case errors.Is(err, repository.ErrConflict):
+		p = problem.New(fiber.StatusConflict, problem.CodeConflict, "status with this name already exists")
+	case errors.Is(err, repository.ErrInvalid):
+		p = problem.New(fiber.StatusUnprocessableEntity, problem.CodeConstraintViolation, "status violates storage constraints")
+	default:
+		slog.Error("failed to "+action+" status", "error", err, "status", name, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to "+action+" status")
+	}
+
+	slog.Warn(action+" status rejected", "error", err, "code", p.Code, "status", name, "ip", c.IP())
+
+	return p
+}
+
+func parseName(c *fiber.Ctx) (string, error) {
+	// Параметры пути ссылаются на буфер запроса, который fiber переиспользует, а название может сохраниться в хранилище
+	name := strings.Clone(c.Params("name"))
+	if len(name) > models.MaxStatusNameLength || !models.StatusNamePattern.MatchString(name) {
+		return "", problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, "invalid status name")
+	}
+
+	return name, nil
+}
@@ -0,0 +1,218 @@
+package tags
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxNameLength = 50
+
+// namePattern допускает буквы, цифры, дефис и подчеркивание, название начинается с буквы или цифры
+var namePattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}_-]*$`)
+
+type Handler struct {
+	repo repository.TagStore
+}
+
+// tagRequest схема тела запроса с данными метки для документации, разбор выполняет decodeTag
+type tagRequest struct {
+	Name string `json:"name" example:"urgent"`
+}
+
+func NewHandler(repo repository.TagStore) *Handler {
+	return &Handler{repo: repo}
+}
+
+// List возвращает список меток
+// @Summary Получить список меток
+// @Description Возвращает все метки, упорядоченные по названию
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Tag "Список меток"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tags [get]
+func (h *Handler) List(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling list tags request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	tags, err := h.repo.ListTags(ctx)
+	if err != nil {
+		slog.Error("failed to list tags", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to list tags")
+	}
+
+	slog.Info("tags listed successfully", "count", len(tags), "ip", c.IP())
+
+	return c.JSON(tags)
+}
+
+// Create создает новую метку
+// @Summary Создать метку
+// @Description Создает метку. Название приводится к нижнему регистру и должно быть уникальным
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param tag body tagRequest true "Данные метки (name)"
+// @Success 200 {object} models.Tag "Созданная метка"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 409 {object} problem.Problem "Метка с таким названием уже существует"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tags [post]
+func (h *Handler) Create(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling create tag request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	name, err := decodeTag(c.Body())
+	if err != nil {
+		slog.Warn("tag creation rejected", "error", err, "ip", c.IP())
+		return problem.Payload(err)
+	}
+
+	t := &models.Tag{Name: name}
+	if err := h.repo.CreateTag(ctx, t); err != nil {
+		return storeError(c, 0, "create", err)
+	}
+
+	slog.Info("tag created successfully", "id", t.ID, "name", t.Name, "ip", c.IP())
+
+	return c.JSON(t)
+}
+
+// Rename переименовывает метку
+// @Summary Переименовать метку
+// @Description Переименовывает метку. Задачи с этой меткой получают новую версию
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID метки"
+// @Param tag body tagRequest true "Новые данные метки (name)"
+// @Success 200 {object} models.Tag "Переименованная метка"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 404 {object} problem.Problem "Метка не найдена"
+// @Failure 409 {object} problem.Problem "Метка с таким названием уже существует"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tags/{id} [put]
+func (h *Handler) Rename(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling rename tag request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid tag ID in rename request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	name, err := decodeTag(c.Body())
+	if err != nil {
+		slog.Warn("tag rename rejected", "error", err, "tag_id", id, "ip", c.IP())
+		return problem.Payload(err)
+	}
+
+	t, err := h.repo.RenameTag(ctx, id, name)
+	if err != nil {
+		return storeError(c, id, "rename", err)
+	}
+
+	slog.Info("tag renamed successfully", "id", id, "name", name, "ip", c.IP())
+
+	return c.JSON(t)
+}
+
+// Delete удаляет метку
+// @Summary Удалить метку
+// @Description Удаляет метку и открепляет ее от всех задач, в том числе удаленных
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID метки"
+// @Success 204 "Метка удалена"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Метка не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tags/{id} [delete]
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling delete tag request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid tag ID in delete request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	if err := h.repo.DeleteTag(ctx, id); err != nil {
+		return storeError(c, id, "delete", err)
+	}
+
+	slog.Info("tag deleted successfully", "id", id, "ip", c.IP())
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// decodeTag разбирает данные метки и возвращает нормализованное название
+func decodeTag(body []byte) (string, error) {
+	var name string
+
+	v := &validation.Validator{}
+
+	if _, err := v.DecodeObject(body, map[string]any{
+		"name": &name,
+	}); err != nil {
+		return "", err
+	}
+
+	name = models.NormalizeTagName(name)
+
+	v.Required("name", name)
+	v.MaxLength("name", name, maxNameLength)
+	if name != "" {
+		v.Matches("name", name, namePattern, "contain only letters, digits, '-' and '_' and start with a letter or digit")
+	}
+
+	return name, v.Err()
+}
+
+// storeError логирует ошибку хранилища при выполнении action над меткой и возвращает ответ на нее.
+// Неизвестные ошибки скрываются за 500
+func storeError(c *fiber.Ctx, id int, action string, err error) error {
+	var p *problem.Problem
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		p = problem.New(fiber.StatusNotFound, problem.CodeTagNotFound, "tag not found")
+	case errors.Is(err, repository.ErrConflict):
+		p = problem.New(fiber.StatusConflict, problem.CodeConflict, "tag with this name already exists")
+	case errors.Is(err, repository.ErrInvalid):
+		p = problem.New(fiber.StatusUnprocessableEntity, problem.CodeConstraintViolation, "tag violates storage constraints")
+	default:
+		slog.Error("failed to "+action+" tag", "error", err, "tag_id", id, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to "+action+" tag")
+	}
+
+	slog.Warn(action+" tag rejected", "error", err, "code", p.Code, "tag_id", id, "ip", c.IP())
+
+	return p
+}
@@ -0,0 +1,248 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best_effort"
+)
+
+type batchRequest struct {
+	// Режим выполнения: atomic (по умолчанию) отменяет весь пакет при первой ошибке,
+	// best_effort применяет каждую операцию независимо
+	Mode       string           `json:"mode" enums:"atomic,best_effort" example:"atomic"`
+	Operations []batchOperation `json:"operations"`
+}
+
+type batchOperation struct {
+	Op string `json:"op" enums:"create,update,delete" example:"update"`
+	// ID задачи для update и delete
+	ID int `json:"id,omitempty" example:"1"`
+	// Ожидаемая версия задачи для update и delete, аналог If-Match
+	Version int `json:"version,omitempty" example:"3"`
+	// Данные задачи: все поля для create, изменяемые поля для update
+	Task json.RawMessage `json:"task,omitempty" swaggertype:"object"`
+	// Перевести задачу в done без проверки блокирующих задач, только для update
+	IgnoreBlockers bool `json:"ignore_blockers,omitempty" example:"false"`
+}
+
+type batchResponse struct {
+	Mode      string        `json:"mode" example:"atomic"`
+	Succeeded int           `json:"succeeded" example:"2"`
+	Failed    int           `json:"failed" example:"0"`
+	Results   []batchResult `json:"results"`
+}
+
+type batchResult struct {
+	Index  int          `json:"index" example:"0"`
+	Op     string       `json:"op" example:"update"`
+	Status int          `json:"status" example:"200"`
+	Task   *models.Task `json:"task,omitempty"`
+	// Ошибка операции в формате RFC 7807
+	Error *problem.Problem `json:"error,omitempty"`
+}
+
+// Batch выполняет пакет операций над задачами
+// @Summary Пакетное изменение задач
+// @Description Выполняет операции create, update и delete в одной транзакции и возвращает результат каждой операции
+// @Description с HTTP статусом. В режиме atomic первая ошибка отменяет весь пакет, остальные операции получают статус 424.
+// @Description В режиме best_effort каждая операция применяется независимо. update изменяет только переданные поля.
+// @Description Данные задач проверяются так же, как в POST /tasks, ошибки полей возвращаются в результате операции со статусом 422
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param batch body batchRequest true "Режим и список операций"
+// @Success 200 {object} batchResponse "Все операции выполнены"
+// @Success 207 {object} batchResponse "Часть операций в режиме best_effort завершилась ошибкой"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 422 {object} batchResponse "Атомарный пакет отменен"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/batch [post]
+func (h *Handler) Batch(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling batch request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	req := batchRequest{}
+	dec := json.NewDecoder(bytes.NewReader(c.Body()))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		slog.Warn("failed to parse batch request body", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidBody, "invalid request: "+err.Error())
+	}
+
+	if req.Mode == "" {
+		req.Mode = batchModeAtomic
+	}
+	if req.Mode != batchModeAtomic && req.Mode != batchModeBestEffort {
+		slog.Warn("batch rejected: invalid mode", "mode", req.Mode, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidBody, "mode must be atomic or best_effort")
+	}
+
+	if len(req.Operations) == 0 || len(req.Operations) > h.cfg.BatchMaxSize {
+		slog.Warn("batch rejected: invalid size", "operations", len(req.Operations), "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidBody, fmt.Sprintf("operations must contain from 1 to %d items", h.cfg.BatchMaxSize))
+	}
+
+	atomic := req.Mode == batchModeAtomic
+	res := batchResponse{Mode: req.Mode, Results: make([]batchResult, len(req.Operations))}
+
+	// индексы операций запроса, переданных в хранилище
+	ops := []repository.BatchOp{}
+	indexes := []int{}
+
+	for i, raw := range req.Operations {
+		res.Results[i] = batchResult{Index: i, Op: raw.Op}
+
+		op, err := parseBatchOp(raw)
+		if err != nil {
+			res.Results[i].fail(err)
+			continue
+		}
+
+		ops = append(ops, op)
+		indexes = append(indexes, i)
+	}
+
+	if atomic && len(ops) < len(req.Operations) {
+		slog.Warn("batch rejected: invalid operations", "ip", c.IP())
+		for i := range res.Results {
+			if res.Results[i].Status == 0 {
+				res.Results[i].fail(errBatchAborted())
+			}
+		}
+
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(res.count())
+	}
+
+	slog.Info("executing batch", "mode", req.Mode, "operations", len(ops), "ip", c.IP())
+
+	if len(ops) > 0 {
+		results, err := h.repo.Batch(ctx, ops, atomic)
+		if err != nil {
+			slog.Error("failed to execute batch", "error", err, "ip", c.IP())
+			return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to execute batch")
+		}
+
+		for j, r := range results {
+			res.Results[indexes[j]].fill(ops[j], r)
+		}
+	}
+
+	res.count()
+
+	slog.Info("batch executed", "mode", req.Mode, "succeeded", res.Succeeded, "failed", res.Failed, "ip", c.IP())
+
+	switch {
+	case res.Failed == 0:
+		return c.JSON(res)
+	case atomic:
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(res)
+	default:
+		return c.Status(fiber.StatusMultiStatus).JSON(res)
+	}
+}
+
+// parseBatchOp проверяет операцию пакета по тем же правилам, что и одиночные запросы.
+// Ошибки возвращаются как *problem.Problem
+func parseBatchOp(raw batchOperation) (repository.BatchOp, *problem.Problem) {
+	op := repository.BatchOp{Kind: repository.BatchOpKind(raw.Op), ID: raw.ID, Version: raw.Version, IgnoreBlockers: raw.IgnoreBlockers}
+
+	switch op.Kind {
+	case repository.BatchCreate:
+		p, err := decodeTaskPayload(raw.Task, false)
+		if err != nil {
+			return op, problem.Payload(err)
+		}
+
+		op.Task = p.task()
+
+		return op, nil
+	case repository.BatchUpdate:
+		if raw.ID <= 0 {
+			return op, problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid id")
+		}
+
+		p, err := decodeTaskPayload(raw.Task, true)
+		if err != nil {
+			return op, problem.Payload(err)
+		}
+
+		op.Updates = p.updates()
+		if len(op.Updates) == 0 {
+			return op, problem.New(fiber.StatusBadRequest, problem.CodeInvalidBody, "task must contain fields to update")
+		}
+
+		return op, nil
+	case repository.BatchDelete:
+		if raw.ID <= 0 {
+			return op, problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid id")
+		}
+
+		return op, nil
+	default:
+		return op, problem.New(fiber.StatusBadRequest, problem.CodeInvalidBody, "op must be create, update or delete")
+	}
+}
+
+// fail заполняет результат неудавшейся операции
+func (r *batchResult) fail(p *problem.Problem) {
+	r.Status = p.Status
+	r.Error = p
+}
+
+func errBatchAborted() *problem.Problem {
+	return problem.New(fiber.StatusFailedDependency, problem.CodeBatchAborted, "operation rolled back: batch aborted")
+}
+
+// fill заполняет результат операции по ответу хранилища
+func (r *batchResult) fill(op repository.BatchOp, res repository.BatchResult) {
+	switch {
+	case res.Err == nil:
+		r.Task = res.Task
+		switch op.Kind {
+		case repository.BatchCreate:
+			r.Status = fiber.StatusCreated
+		case repository.BatchDelete:
+			r.Status = fiber.StatusNoContent
+		default:
+			r.Status = fiber.StatusOK
+		}
+	case errors.Is(res.Err, repository.ErrBatchAborted):
+		r.fail(errBatchAborted())
+	default:
+		p := storeProblem(res.Err)
+		if p == nil {
+			slog.Error("batch operation failed", "error", res.Err, "index", r.Index, "op", r.Op)
+			p = problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to apply operation")
+		}
+		r.fail(p)
+	}
+}
+
+// count подсчитывает успешные и неудавшиеся операции
+func (res *batchResponse) count() *batchResponse {
+	res.Succeeded, res.Failed = 0, 0
+	for _, r := range res.Results {
+		if r.Error == nil {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,117 @@
+package tasks
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AddBlocker добавляет задаче блокирующую задачу
+// @Summary Добавить блокирующую задачу
+// @Description Делает задачу blocker_id блокирующей для задачи id: пока блокирующая задача не выполнена,
+// @Description задачу id нельзя перевести в статус done. Повторное добавление не изменяет задачу и ее версию
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Param blocker_id path int true "ID блокирующей задачи"
+// @Success 200 {object} models.TaskDetail "Задача со связями блокировки"
+// @Header 200 {string} ETag "Версия задачи"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача или блокирующая задача не найдена"
+// @Failure 409 {object} problem.Problem "Связь образует цикл"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/blockers/{blocker_id} [put]
+func (h *Handler) AddBlocker(c *fiber.Ctx) error {
+	return h.changeBlocker(c, "block", h.repo.AddBlocker)
+}
+
+// RemoveBlocker удаляет блокирующую задачу
+// @Summary Удалить блокирующую задачу
+// @Description Удаляет связь блокировки между задачами. Если связи не было, задача и ее версия не изменяются
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Param blocker_id path int true "ID блокирующей задачи"
+// @Success 200 {object} models.TaskDetail "Задача со связями блокировки"
+// @Header 200 {string} ETag "Версия задачи"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/blockers/{blocker_id} [delete]
+func (h *Handler) RemoveBlocker(c *fiber.Ctx) error {
+	return h.changeBlocker(c, "unblock", h.repo.RemoveBlocker)
+}
+
+func (h *Handler) changeBlocker(c *fiber.Ctx, action string, change func(ctx context.Context, taskID, blockerID int) (*models.Task, error)) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling "+action+" task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in "+action+" request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	blockerID, err := strconv.Atoi(c.Params("blocker_id"))
+	if err != nil || blockerID <= 0 {
+		slog.Warn("invalid blocker ID in "+action+" request", "blocker_id", c.Params("blocker_id"), "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid blocker id")
+	}
+
+	t, err := change(ctx, id, blockerID)
+	if err != nil {
+		return storeError(c, id, action, err)
+	}
+
+	detail, err := h.detail(ctx, t)
+	if err != nil {
+		return storeError(c, id, action, err)
+	}
+
+	slog.Info("task blocker changed successfully", "action", action, "id", id, "blocker_id", blockerID, "ip", c.IP())
+
+	c.Set(fiber.HeaderETag, taskETag(t))
+
+	return c.JSON(detail)
+}
+
+// detail дополняет задачу связями блокировки
+func (h *Handler) detail(ctx context.Context, t *models.Task) (*models.TaskDetail, error) {
+	blockedBy, blocks, err := h.repo.Dependencies(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskDetail{Task: *t, BlockedBy: blockedBy, Blocks: blocks}, nil
+}
+
+// updateOptions возвращает параметры изменения задачи. Проверка блокирующих задач отключается
+// параметром ignore_blockers=true
+func updateOptions(c *fiber.Ctx) (repository.UpdateOptions, error) {
+	opts := repository.UpdateOptions{}
+
+	raw := c.Query("ignore_blockers")
+	if raw == "" {
+		return opts, nil
+	}
+
+	ignore, err := strconv.ParseBool(raw)
+	if err != nil {
+		slog.Warn("invalid ignore_blockers parameter", "ignore_blockers", raw, "ip", c.IP())
+		return opts, problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, "ignore_blockers must be true or false")
+	}
+	opts.IgnoreBlockers = ignore
+
+	return opts, nil
+}
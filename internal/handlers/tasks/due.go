@@ -0,0 +1,160 @@
+package tasks
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultDueWithin = 24 * time.Hour
+	maxDueWithin     = 366 * 24 * time.Hour
+)
+
+// dueOrder сортировка представлений по срокам: сначала важные задачи, среди них - с ближайшим сроком
+var dueOrder = []repository.SortKey{{Field: "priority", Desc: true}, {Field: "due_at"}}
+
+// Overdue возвращает просроченные задачи
+// @Summary Получить просроченные задачи
+// @Description Возвращает невыполненные задачи, срок которых уже наступил. По умолчанию задачи упорядочены
+// @Description по приоритету (сначала urgent), затем по сроку. Поддерживает те же параметры фильтрации,
+// @Description сортировки и пагинации, что и GET /tasks, границы due_after и due_before сужают представление
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param priority query []string false "Приоритет задачи, можно указать несколько" collectionFormat(multi) Enums(low, normal, high, urgent)
+// @Param project_id query int false "ID проекта"
+// @Param due_after query string false "Срок не раньше (RFC 3339 или YYYY-MM-DD), сужает представление"
+// @Param due_before query string false "Срок раньше (RFC 3339 или YYYY-MM-DD), сужает представление"
+// @Param tag query []string false "Название метки, можно указать несколько" collectionFormat(multi)
+// @Param tag_mode query string false "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)" Enums(any, all)
+// @Param sort query string false "Сортировка, по умолчанию -priority,due_at"
+// @Param limit query int false "Размер страницы в режиме курсора"
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor предыдущей страницы"
+// @Param page query int false "Номер страницы в постраничном режиме, начиная с 1"
+// @Param per_page query int false "Размер страницы в постраничном режиме"
+// @Success 200 {array} models.Task "Просроченные задачи"
+// @Header 200 {integer} X-Total-Count "Общее количество просроченных задач"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Header 200 {string} Link "Ссылки на соседние страницы"
+// @Failure 400 {object} problem.Problem "Неверные параметры фильтрации, сортировки или пагинации"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/overdue [get]
+func (h *Handler) Overdue(c *fiber.Ctx) error {
+	if slog.Default().Enabled(c.Context(), slog.LevelDebug) {
+		slog.Debug("handling list overdue tasks request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	now := time.Now().UTC()
+
+	return h.list(c, func(p *repository.ListParams) {
+		p.Filter.Open = true
+		p.Filter.DueBefore = earliest(p.Filter.DueBefore, now)
+		if len(p.Sort) == 0 {
+			p.Sort = dueOrder
+		}
+	})
+}
+
+// Due возвращает задачи, срок которых наступит в ближайшее время
+// @Summary Получить задачи с приближающимся сроком
+// @Description Возвращает невыполненные задачи, срок которых наступит в течение within (по умолчанию 24h).
+// @Description Просроченные задачи не включаются, их возвращает GET /tasks/overdue. По умолчанию задачи упорядочены
+// @Description по приоритету (сначала urgent), затем по сроку. Поддерживает те же параметры фильтрации,
+// @Description сортировки и пагинации, что и GET /tasks, границы due_after и due_before сужают представление
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param within query string false "Период в формате Go duration (48h, 90m) или в днях (7d)" example(48h)
+// @Param priority query []string false "Приоритет задачи, можно указать несколько" collectionFormat(multi) Enums(low, normal, high, urgent)
+// @Param project_id query int false "ID проекта"
+// @Param due_after query string false "Срок не раньше (RFC 3339 или YYYY-MM-DD), сужает представление"
+// @Param due_before query string false "Срок раньше (RFC 3339 или YYYY-MM-DD), сужает представление"
+// @Param tag query []string false "Название метки, можно указать несколько" collectionFormat(multi)
+// @Param tag_mode query string false "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)" Enums(any, all)
+// @Param sort query string false "Сортировка, по умолчанию -priority,due_at"
+// @Param limit query int false "Размер страницы в режиме курсора"
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor предыдущей страницы"
+// @Param page query int false "Номер страницы в постраничном режиме, начиная с 1"
+// @Param per_page query int false "Размер страницы в постраничном режиме"
+// @Success 200 {array} models.Task "Задачи с приближающимся сроком"
+// @Header 200 {integer} X-Total-Count "Общее количество задач"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Header 200 {string} Link "Ссылки на соседние страницы"
+// @Failure 400 {object} problem.Problem "Неверный параметр within или параметры списка"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/due [get]
+func (h *Handler) Due(c *fiber.Ctx) error {
+	if slog.Default().Enabled(c.Context(), slog.LevelDebug) {
+		slog.Debug("handling list due tasks request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	within, err := parseWithin(c.Query("within"))
+	if err != nil {
+		slog.Warn("invalid within parameter", "within", c.Query("within"), "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, err.Error())
+	}
+
+	now := time.Now().UTC()
+	until := now.Add(within)
+
+	return h.list(c, func(p *repository.ListParams) {
+		p.Filter.Open = true
+		p.Filter.DueAfter = latest(p.Filter.DueAfter, now)
+		p.Filter.DueBefore = earliest(p.Filter.DueBefore, until)
+		if len(p.Sort) == 0 {
+			p.Sort = dueOrder
+		}
+	})
+}
+
+// earliest возвращает более раннюю из границы bound, заданной в запросе, и t
+func earliest(bound *time.Time, t time.Time) *time.Time {
+	if bound != nil && bound.Before(t) {
+		return bound
+	}
+
+	return &t
+}
+
+// latest возвращает более позднюю из границы bound, заданной в запросе, и t
+func latest(bound *time.Time, t time.Time) *time.Time {
+	if bound != nil && bound.After(t) {
+		return bound
+	}
+
+	return &t
+}
+
+// parseWithin разбирает период within: Go duration или целое число дней с суффиксом d
+func parseWithin(raw string) (time.Duration, error) {
+	if raw == "" {
+		return defaultDueWithin, nil
+	}
+
+	var (
+		d   time.Duration
+		err error
+	)
+
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		var n int
+		if n, err = strconv.Atoi(days); err == nil && n <= int(maxDueWithin/(24*time.Hour)) {
+			d = time.Duration(n) * 24 * time.Hour
+		}
+	} else {
+		d, err = time.ParseDuration(raw)
+	}
+
+	if err != nil || d <= 0 || d > maxDueWithin {
+		return 0, fmt.Errorf("within must be a positive duration up to 366d such as 48h or 7d")
+	}
+
+	return d, nil
+}
@@ -0,0 +1,74 @@
+package tasks
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDueBoundsNarrowScope(t *testing.T) {
+	app := newTestApp(t)
+	now := time.Now().UTC()
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+
+	for _, task := range []struct {
+		title string
+		due   time.Duration
+	}{
+		{"old", -72 * time.Hour},
+		{"recent", -2 * time.Hour},
+		{"soon", 2 * time.Hour},
+		{"later", 10 * time.Hour},
+		{"far", 72 * time.Hour},
+	} {
+		body := `{"title":"` + task.title + `","due_at":"` + at(task.due) + `"}`
+		if resp, b := do(t, app, fiber.MethodPost, "/tasks", body); resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("create %s: status = %d: %s", task.title, resp.StatusCode, b)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		params url.Values
+		want   []string
+	}{
+		{"overdue", "/tasks/overdue", nil, []string{"old", "recent"}},
+		{"overdue due_after", "/tasks/overdue", url.Values{"due_after": {at(-24 * time.Hour)}}, []string{"recent"}},
+		{"overdue due_before", "/tasks/overdue", url.Values{"due_before": {at(-24 * time.Hour)}}, []string{"old"}},
+		{"overdue due_before after now", "/tasks/overdue", url.Values{"due_before": {at(48 * time.Hour)}}, []string{"old", "recent"}},
+		{"due", "/tasks/due", nil, []string{"soon", "later"}},
+		{"due due_before", "/tasks/due", url.Values{"due_before": {at(5 * time.Hour)}}, []string{"soon"}},
+		{"due due_after", "/tasks/due", url.Values{"due_after": {at(5 * time.Hour)}}, []string{"later"}},
+		{"due due_after before now", "/tasks/due", url.Values{"due_after": {at(-48 * time.Hour)}}, []string{"soon", "later"}},
+		{"due both bounds outside window", "/tasks/due", url.Values{"due_after": {at(-48 * time.Hour)}, "due_before": {at(96 * time.Hour)}}, []string{"soon", "later"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := do(t, app, fiber.MethodGet, tt.path+"?"+tt.params.Encode(), "")
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, fiber.StatusOK, body)
+			}
+
+			var tasks []models.Task
+			if err := json.Unmarshal(body, &tasks); err != nil {
+				t.Fatalf("decode tasks %s: %v", body, err)
+			}
+
+			got := []string{}
+			for _, task := range tasks {
+				got = append(got, task.Title)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("titles = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
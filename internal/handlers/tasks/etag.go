@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// errPreconditionFailed возвращается, когда If-Match не совпадает с текущей версией задачи
+var errPreconditionFailed = errors.New("precondition failed")
+
+// taskETag сильный ETag задачи, построенный по ее версии
+func taskETag(t *models.Task) string {
+	return `"` + strconv.Itoa(t.Version) + `"`
+}
+
+// listETag слабый ETag страницы списка: меняется при изменении, создании или удалении любой задачи страницы
+func listETag(tasks []models.Task, total int) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%d;", total)
+	for _, t := range tasks {
+		fmt.Fprintf(h, "%d:%d;", t.ID, t.Version)
+	}
+
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:8]) + `"`
+}
+
+// expectedVersion возвращает версию задачи, которой должен соответствовать заголовок If-Match,
+// или 0, если заголовок не задан или равен "*". If-Match сравнивается строго, поэтому слабые
+// и нечисловые ETag не совпадают ни с одной версией. Если в заголовке перечислено несколько версий,
+// выбирается текущая версия задачи, а хранилище повторно проверит ее атомарно
+func (h *Handler) expectedVersion(ctx context.Context, c *fiber.Ctx, id int) (int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return 0, nil
+	}
+
+	versions := []int{}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return 0, nil
+		}
+		if !strings.HasPrefix(candidate, `"`) {
+			continue
+		}
+
+		if v, err := strconv.Atoi(strings.Trim(candidate, `"`)); err == nil && v > 0 {
+			versions = append(versions, v)
+		}
+	}
+
+	switch len(versions) {
+	case 0:
+		return 0, errPreconditionFailed
+	case 1:
+		return versions[0], nil
+	}
+
+	t, err := h.repo.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if !slices.Contains(versions, t.Version) {
+		return 0, errPreconditionFailed
+	}
+
+	return t.Version, nil
+}
+
+// notModified сообщает, совпадает ли etag с одним из значений If-None-Match (слабое сравнение)
+func notModified(c *fiber.Ctx, etag string) bool {
+	header := c.Get(fiber.HeaderIfNoneMatch)
+	if header == "" {
+		return false
+	}
+
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseFilter разбирает параметры фильтрации списка задач из query string
+func parseFilter(c *fiber.Ctx) (repository.TaskFilter, error) {
+	f := repository.TaskFilter{
+		Text: strings.TrimSpace(c.Query("q")),
+	}
+
+	for _, raw := range c.Context().QueryArgs().PeekMulti("status") {
+		for _, s := range strings.Split(string(raw), ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			if !isValidStatus(s) {
+				return f, fmt.Errorf("invalid status %q", s)
+			}
+			f.Statuses = append(f.Statuses, s)
+		}
+	}
+
+	for _, raw := range c.Context().QueryArgs().PeekMulti("priority") {
+		for _, p := range strings.Split(string(raw), ",") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			if !slices.Contains(models.TaskPriorities, p) {
+				return f, fmt.Errorf("invalid priority %q", p)
+			}
+			f.Priorities = append(f.Priorities, p)
+		}
+	}
+
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return f, fmt.Errorf("project_id must be a positive integer")
+		}
+		f.ProjectID = &id
+	}
+
+	if raw := c.Query("series_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return f, fmt.Errorf("series_id must be a positive integer")
+		}
+		f.SeriesID = &id
+	}
+
+	for _, raw := range c.Context().QueryArgs().PeekMulti("tag") {
+		for _, name := range strings.Split(string(raw), ",") {
+			name = models.NormalizeTagName(name)
+			if name != "" && !slices.Contains(f.Tags, name) {
+				f.Tags = append(f.Tags, name)
+			}
+		}
+	}
+
+	switch c.Query("tag_mode", "any") {
+	case "any":
+	case "all":
+		f.AllTags = true
+	default:
+		return f, fmt.Errorf("tag_mode must be any or all")
+	}
+
+	bounds := []struct {
+		key string
+		dst **time.Time
+	}{
+		{"created_after", &f.CreatedAfter},
+		{"created_before", &f.CreatedBefore},
+		{"updated_after", &f.UpdatedAfter},
+		{"updated_before", &f.UpdatedBefore},
+		{"due_after", &f.DueAfter},
+		{"due_before", &f.DueBefore},
+	}
+
+	for _, b := range bounds {
+		raw := c.Query(b.key)
+		if raw == "" {
+			continue
+		}
+
+		t, err := params.Time(raw)
+		if err != nil {
+			return f, fmt.Errorf("%s must be a RFC 3339 timestamp or a YYYY-MM-DD date", b.key)
+		}
+		*b.dst = &t
+	}
+
+	return f, nil
+}
+
+// isValidStatus проверяет формат названия статуса. Существование статуса проверяет хранилище
+func isValidStatus(s string) bool {
+	return len(s) <= models.MaxStatusNameLength && models.StatusNamePattern.MatchString(s)
+}
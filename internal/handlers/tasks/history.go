@@ -0,0 +1,48 @@
+package tasks
+
+import (
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/gofiber/fiber/v2"
+)
+
+// History возвращает историю статусов задачи
+// @Summary Получить историю статусов задачи
+// @Description Возвращает создание задачи и все изменения ее статуса в порядке изменений, в том числе выполнение
+// @Description вместе с родительской задачей. Автор изменения передается в заголовке X-Actor запроса, который его выполнил
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Success 200 {array} models.StatusChange "История статусов"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/history [get]
+func (h *Handler) History(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling get task history request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in get history request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	if _, err := h.repo.Get(ctx, id); err != nil {
+		return storeError(c, id, "get", err)
+	}
+
+	history, err := h.repo.History(ctx, id)
+	if err != nil {
+		return storeError(c, id, "get history of", err)
+	}
+
+	slog.Info("task history retrieved successfully", "id", id, "count", len(history), "ip", c.IP())
+
+	return c.JSON(history)
+}
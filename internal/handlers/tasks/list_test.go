@@ -0,0 +1,178 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/config"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// newTestApp возвращает приложение с маршрутами задач поверх нового хранилища в памяти
+func newTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	return newStoreTestApp(t, repository.NewMemoryTaskRepository())
+}
+
+// newStoreTestApp возвращает приложение с маршрутами задач поверх store.
+// Ошибки обработчиков отдаются как problem.Problem, как это делает сервер
+func newStoreTestApp(t *testing.T, store repository.Store) *fiber.App {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			var p *problem.Problem
+			if !errors.As(err, &p) {
+				t.Errorf("handler returned non-problem error: %v", err)
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+
+			return c.Status(p.Status).JSON(p, problem.ContentType)
+		},
+	})
+
+	h := NewHandler(store, &config.ConfServer{PageSizeDefault: 20, PageSizeMax: 100})
+	app.Get("/tasks", h.List)
+	app.Get("/tasks/overdue", h.Overdue)
+	app.Get("/tasks/due", h.Due)
+	app.Post("/tasks", h.Create)
+	app.Put("/tasks/:id", h.Update)
+
+	return app
+}
+
+// do выполняет запрос и возвращает ответ вместе с телом
+func do(t *testing.T, app *fiber.App, method, target, body string) (*http.Response, []byte) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: read body: %v", method, target, err)
+	}
+
+	return resp, b
+}
+
+// decodeProblem разбирает тело ответа с ошибкой
+func decodeProblem(t *testing.T, body []byte) problem.Problem {
+	t.Helper()
+
+	var p problem.Problem
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatalf("decode problem %s: %v", body, err)
+	}
+
+	return p
+}
+
+func TestListSortParameter(t *testing.T) {
+	app := newTestApp(t)
+
+	tests := []struct {
+		sort   string
+		status int
+	}{
+		{"", fiber.StatusOK},
+		{"title", fiber.StatusOK},
+		{"-updated_at,title", fiber.StatusOK},
+		{" -due_at , priority ", fiber.StatusOK},
+		{"unknown", fiber.StatusBadRequest},
+		{"title,-title", fiber.StatusBadRequest},
+		{"title,", fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			resp, body := do(t, app, fiber.MethodGet, "/tasks?sort="+url.QueryEscape(tt.sort), "")
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+
+			if tt.status == fiber.StatusBadRequest {
+				if p := decodeProblem(t, body); p.Code != problem.CodeInvalidParameter {
+					t.Errorf("code = %s, want %s", p.Code, problem.CodeInvalidParameter)
+				}
+			}
+		})
+	}
+}
+
+func TestListCursor(t *testing.T) {
+	app := newTestApp(t)
+
+	for _, title := range []string{"a", "b", "c"} {
+		if resp, body := do(t, app, fiber.MethodPost, "/tasks", `{"title":"`+title+`"}`); resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("create %s: status = %d: %s", title, resp.StatusCode, body)
+		}
+	}
+
+	resp, body := do(t, app, fiber.MethodGet, "/tasks?sort=-title&limit=2", "")
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("first page: status = %d: %s", resp.StatusCode, body)
+	}
+
+	cursor := resp.Header.Get(headerNextCursor)
+	if cursor == "" {
+		t.Fatalf("first page: no %s header", headerNextCursor)
+	}
+
+	resp, body = do(t, app, fiber.MethodGet, "/tasks?sort=-title&limit=2&cursor="+cursor, "")
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("second page: status = %d: %s", resp.StatusCode, body)
+	}
+
+	var page []struct{ Title string }
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatalf("decode second page: %v", err)
+	}
+	if len(page) != 1 || page[0].Title != "a" {
+		t.Errorf("second page = %+v, want [a]", page)
+	}
+	if next := resp.Header.Get(headerNextCursor); next != "" {
+		t.Errorf("second page: unexpected %s %q", headerNextCursor, next)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"tampered", "sort=-title&cursor=" + cursor[:len(cursor)-3] + "xyz"},
+		{"garbage", "sort=-title&cursor=not-a-cursor"},
+		{"different sort", "sort=title&cursor=" + cursor},
+		{"default sort", "cursor=" + cursor},
+		{"combined with page", "sort=-title&page=2&cursor=" + cursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := do(t, app, fiber.MethodGet, "/tasks?"+tt.query, "")
+			if resp.StatusCode != fiber.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, fiber.StatusBadRequest, body)
+			}
+
+			if p := decodeProblem(t, body); p.Code != problem.CodeInvalidParameter {
+				t.Errorf("code = %s, want %s", p.Code, problem.CodeInvalidParameter)
+			}
+		})
+	}
+}
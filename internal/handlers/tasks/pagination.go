@@ -0,0 +1,115 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	headerTotalCount = "X-Total-Count"
+	headerNextCursor = "X-Next-Cursor"
+)
+
+// pagination разобранные параметры пагинации списка.
+// Если задан page или per_page, используется постраничный режим, иначе keyset по курсору
+type pagination struct {
+	params   repository.ListParams
+	pageMode bool
+	page     int
+}
+
+func (h *Handler) parsePagination(c *fiber.Ctx) (*pagination, error) {
+	if c.Query("page") != "" || c.Query("per_page") != "" {
+		if c.Query("cursor") != "" || c.Query("limit") != "" {
+			return nil, errors.New("page/per_page cannot be combined with cursor/limit")
+		}
+
+		return h.parsePages(c)
+	}
+
+	p := &pagination{}
+
+	limit, err := params.Int(c, "limit", h.cfg.PageSizeDefault, h.cfg.PageSizeMax)
+	if err != nil {
+		return nil, err
+	}
+	p.params.Limit = limit
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := repository.DecodeCursor(raw)
+		if err != nil {
+			return nil, err
+		}
+		p.params.After = cursor
+	}
+
+	return p, nil
+}
+
+// parsePages разбирает параметры постраничного режима page и per_page
+func (h *Handler) parsePages(c *fiber.Ctx) (*pagination, error) {
+	page, err := params.Int(c, "page", 1, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	perPage, err := params.Int(c, "per_page", h.cfg.PageSizeDefault, h.cfg.PageSizeMax)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pagination{pageMode: true, page: page}
+	p.params.Limit = perPage
+	p.params.Offset = (page - 1) * perPage
+
+	return p, nil
+}
+
+// setHeaders выставляет X-Total-Count, X-Next-Cursor и Link (RFC 8288) для страницы результата
+func (p *pagination) setHeaders(c *fiber.Ctx, total int, next *repository.Cursor) {
+	c.Set(headerTotalCount, strconv.Itoa(total))
+
+	links := []string{}
+
+	if !p.pageMode {
+		if next != nil {
+			encoded := next.Encode()
+			c.Set(headerNextCursor, encoded)
+			links = append(links, pageLink(c, "next", map[string]string{"cursor": encoded}))
+		}
+	} else {
+		perPage := p.params.Limit
+		last := max(1, (total+perPage-1)/perPage)
+
+		links = append(links, pageLink(c, "first", map[string]string{"page": "1"}))
+		if p.page > 1 {
+			links = append(links, pageLink(c, "prev", map[string]string{"page": strconv.Itoa(min(p.page-1, last))}))
+		}
+		if p.page < last {
+			links = append(links, pageLink(c, "next", map[string]string{"page": strconv.Itoa(p.page + 1)}))
+		}
+		links = append(links, pageLink(c, "last", map[string]string{"page": strconv.Itoa(last)}))
+	}
+
+	if len(links) > 0 {
+		c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+	}
+}
+
+// pageLink строит ссылку на текущий ресурс, сохраняя параметры запроса и заменяя указанные
+func pageLink(c *fiber.Ctx, rel string, set map[string]string) string {
+	q, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	for k, v := range set {
+		q.Set(k, v)
+	}
+
+	return fmt.Sprintf(`<%s%s?%s>; rel="%s"`, c.BaseURL(), c.Path(), q.Encode(), rel)
+}
@@ -0,0 +1,184 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	mimeMergePatch = "application/merge-patch+json"
+	mimeJSONPatch  = "application/json-patch+json"
+)
+
+// patchDocument редактируемое представление задачи, к которому применяется патч.
+// Пустое описание представлено как null
+type patchDocument struct {
+	Title       string     `json:"title" example:"Купить молоко"`
+	Description *string    `json:"description" example:"Взять 2 литра и хлеб"`
+	Status      string     `json:"status" example:"in_progress"`
+	ProjectID   *int       `json:"project_id" example:"2"`
+	ParentID    *int       `json:"parent_id" example:"7"`
+	Priority    string     `json:"priority" example:"high"`
+	DueAt       *time.Time `json:"due_at" example:"2025-08-20T18:00:00Z"`
+	Recurrence  *string    `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=MO"`
+}
+
+// patchFunc применяет патч к JSON документу задачи
+type patchFunc func(doc []byte) ([]byte, error)
+
+// Patch частично обновляет задачу
+// @Summary Частично обновить задачу
+// @Description Применяет к задаче JSON Merge Patch (RFC 7396, Content-Type application/merge-patch+json)
+// @Description или JSON Patch (RFC 6902, Content-Type application/json-patch+json).
+// @Description Патч применяется к документу {title, description, status, project_id, parent_id, priority, due_at, recurrence} в одной транзакции с чтением задачи.
+// @Description Статус меняется только по переходам, которые допускает процесс (GET /statuses).
+// @Description Перевод задачи в статус категории done выполняет все ее подзадачи, создает следующее повторение повторяющейся задачи и запрещен, пока задачу или ее подзадачи блокируют
+// @Description невыполненные задачи, если не передан ignore_blockers=true
+// @Tags tasks
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Param patch body patchDocument true "Merge Patch документ или массив операций JSON Patch"
+// @Param ignore_blockers query bool false "Выполнить задачу без проверки блокирующих задач"
+// @Param If-Match header string false "ETag версии задачи, которую изменяет клиент"
+// @Success 200 {object} models.Task "Обновленная задача"
+// @Header 200 {string} ETag "Новая версия задачи"
+// @Failure 400 {object} problem.Problem "Неверный патч"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 409 {object} problem.Problem "Патч не применим к текущему состоянию задачи, проект в архиве, процесс не допускает переход в новый статус или задачу блокируют невыполненные задачи"
+// @Failure 412 {object} problem.Problem "Задача была изменена другим клиентом"
+// @Failure 415 {object} problem.Problem "Неподдерживаемый формат патча"
+// @Failure 422 {object} problem.Problem "Результат патча не прошел проверку"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id} [patch]
+func (h *Handler) Patch(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling patch task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in patch request", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid id")
+	}
+
+	apply, err := parsePatch(c)
+	if err != nil {
+		slog.Warn("patch rejected", "error", err, "task_id", id, "ip", c.IP())
+		return err
+	}
+
+	opts, err := updateOptions(c)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("patching task", "id", id, "content_type", c.Get(fiber.HeaderContentType), "ip", c.IP())
+
+	version, err := h.expectedVersion(ctx, c, id)
+	if err != nil {
+		return storeError(c, id, "update", err)
+	}
+
+	t, err := h.repo.Modify(ctx, id, func(t *models.Task) error {
+		return applyPatch(t, apply)
+	}, version, opts)
+	if err != nil {
+		var p *problem.Problem
+		if errors.As(err, &p) {
+			slog.Warn("patch rejected", "error", err, "task_id", id, "ip", c.IP())
+			return p
+		}
+
+		return storeError(c, id, "update", err)
+	}
+
+	slog.Info("task patched successfully", "id", id, "version", t.Version, "ip", c.IP())
+
+	c.Set(fiber.HeaderETag, taskETag(t))
+
+	return c.JSON(t)
+}
+
+// parsePatch выбирает формат патча по Content-Type и проверяет синтаксис тела запроса
+func parsePatch(c *fiber.Ctx) (patchFunc, error) {
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	body := bytes.Clone(c.Body())
+
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case mimeMergePatch:
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return nil, problem.New(fiber.StatusBadRequest, problem.CodeInvalidPatch, "merge patch must be a JSON object")
+		}
+
+		return func(doc []byte) ([]byte, error) {
+			return jsonpatch.MergePatch(doc, body)
+		}, nil
+	case mimeJSONPatch:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return nil, problem.New(fiber.StatusBadRequest, problem.CodeInvalidPatch, "json patch must be an array of operations")
+		}
+
+		return patch.Apply, nil
+	default:
+		return nil, problem.New(
+			fiber.StatusUnsupportedMediaType,
+			problem.CodeUnsupportedMediaType,
+			fmt.Sprintf("content type must be %s or %s", mimeMergePatch, mimeJSONPatch),
+		)
+	}
+}
+
+// applyPatch применяет патч к задаче и проверяет получившийся документ
+func applyPatch(t *models.Task, apply patchFunc) error {
+	doc := patchDocument{
+		Title:      t.Title,
+		Status:     t.Status,
+		ProjectID:  t.ProjectID,
+		ParentID:   t.ParentID,
+		Priority:   t.Priority,
+		DueAt:      t.DueAt,
+		Recurrence: t.Recurrence,
+	}
+	if t.Description != "" {
+		doc.Description = &t.Description
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	patched, err := apply(raw)
+	if err != nil {
+		return problem.New(fiber.StatusConflict, problem.CodePatchConflict, "failed to apply patch: "+err.Error())
+	}
+
+	// результат патча проверяется как полная замена задачи
+	p, err := decodeTaskPayload(patched, false)
+	if err != nil {
+		return problem.Payload(err)
+	}
+
+	result := p.task()
+	t.Title, t.Description, t.Status, t.ProjectID = result.Title, result.Description, result.Status, result.ProjectID
+	t.ParentID, t.Priority, t.DueAt, t.Recurrence = result.ParentID, result.Priority, result.DueAt, result.Recurrence
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package tasks
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProjectTasks возвращает страницу задач проекта
+// @Summary Получить задачи проекта
+// @Description Возвращает страницу активных задач проекта. Поддерживает те же параметры фильтрации, сортировки
+// @Description и пагинации, что и GET /tasks
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param status query []string false "Статус задачи, можно указать несколько" collectionFormat(multi)
+// @Param q query string false "Подстрока заголовка или описания"
+// @Param priority query []string false "Приоритет задачи, можно указать несколько" collectionFormat(multi) Enums(low, normal, high, urgent)
+// @Param due_after query string false "Срок не раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param due_before query string false "Срок раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param tag query []string false "Название метки, можно указать несколько" collectionFormat(multi)
+// @Param tag_mode query string false "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)" Enums(any, all)
+// @Param sort query string false "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания"
+// @Param limit query int false "Размер страницы в режиме курсора"
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor предыдущей страницы"
+// @Param page query int false "Номер страницы в постраничном режиме, начиная с 1"
+// @Param per_page query int false "Размер страницы в постраничном режиме"
+// @Success 200 {array} models.Task "Список задач проекта"
+// @Header 200 {integer} X-Total-Count "Общее количество задач проекта"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Header 200 {string} Link "Ссылки на соседние страницы"
+// @Failure 400 {object} problem.Problem "Неверный ID или параметры списка"
+// @Failure 404 {object} problem.Problem "Проект не найден"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /projects/{id}/tasks [get]
+func (h *Handler) ProjectTasks(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling list project tasks request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid project ID in list tasks request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	if _, err := h.repo.GetProject(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			slog.Warn("project not found", "project_id", id, "ip", c.IP())
+			return problem.New(fiber.StatusNotFound, problem.CodeProjectNotFound, "project not found")
+		}
+
+		slog.Error("failed to get project", "error", err, "project_id", id, "ip", c.IP())
+
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to get project")
+	}
+
+	return h.list(c, func(p *repository.ListParams) {
+		p.Filter.ProjectID = &id
+	})
+}
@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseSort разбирает параметр sort вида "-updated_at,title": ключи через запятую,
+// префикс "-" означает сортировку по убыванию
+func parseSort(c *fiber.Ctx) ([]repository.SortKey, error) {
+	raw := c.Query("sort")
+	if raw == "" {
+		return nil, nil
+	}
+
+	keys := []repository.SortKey{}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+
+		key := repository.SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !slices.Contains(repository.SortableFields, key.Field) {
+			return nil, fmt.Errorf("unknown sort field %q, allowed: %s", key.Field, strings.Join(repository.SortableFields, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", key.Field)
+		}
+		seen[key.Field] = true
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,113 @@
+package tasks
+
+import (
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Subtasks возвращает страницу подзадач задачи
+// @Summary Получить подзадачи
+// @Description Возвращает страницу активных подзадач первого уровня. Поддерживает те же параметры фильтрации,
+// @Description сортировки и пагинации, что и GET /tasks
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Param status query []string false "Статус задачи, можно указать несколько" collectionFormat(multi)
+// @Param q query string false "Подстрока заголовка или описания"
+// @Param priority query []string false "Приоритет задачи, можно указать несколько" collectionFormat(multi) Enums(low, normal, high, urgent)
+// @Param tag query []string false "Название метки, можно указать несколько" collectionFormat(multi)
+// @Param tag_mode query string false "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)" Enums(any, all)
+// @Param sort query string false "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания"
+// @Param limit query int false "Размер страницы в режиме курсора"
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor предыдущей страницы"
+// @Param page query int false "Номер страницы в постраничном режиме, начиная с 1"
+// @Param per_page query int false "Размер страницы в постраничном режиме"
+// @Success 200 {array} models.Task "Подзадачи"
+// @Header 200 {integer} X-Total-Count "Общее количество подзадач"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Header 200 {string} Link "Ссылки на соседние страницы"
+// @Failure 400 {object} problem.Problem "Неверный ID или параметры списка"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/subtasks [get]
+func (h *Handler) Subtasks(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling list subtasks request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in list subtasks request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	if _, err := h.repo.Get(ctx, id); err != nil {
+		return storeError(c, id, "get", err)
+	}
+
+	return h.list(c, func(p *repository.ListParams) {
+		p.Filter.ParentID = &id
+	})
+}
+
+// Tree возвращает задачу вместе со всеми подзадачами
+// @Summary Получить дерево задачи
+// @Description Возвращает задачу и ее активные подзадачи всех уровней. Подзадачи каждого уровня упорядочены по дате создания
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Success 200 {object} models.TaskTree "Дерево задачи"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/tree [get]
+func (h *Handler) Tree(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling get task tree request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in get tree request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	tasks, err := h.repo.Subtree(ctx, id)
+	if err != nil {
+		return storeError(c, id, "get tree of", err)
+	}
+
+	slog.Info("task tree retrieved successfully", "id", id, "count", len(tasks), "ip", c.IP())
+
+	return c.JSON(buildTree(id, tasks))
+}
+
+// buildTree собирает дерево с корнем root из задач, упорядоченных по дате создания
+func buildTree(root int, tasks []models.Task) *models.TaskTree {
+	nodes := make(map[int]*models.TaskTree, len(tasks))
+	for _, t := range tasks {
+		nodes[t.ID] = &models.TaskTree{Task: t, Children: []*models.TaskTree{}}
+	}
+
+	for _, t := range tasks {
+		if t.ID == root || t.ParentID == nil {
+			continue
+		}
+
+		if parent, ok := nodes[*t.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[t.ID])
+		}
+	}
+
+	return nodes[root]
+}
@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AttachTag прикрепляет метку к задаче
+// @Summary Прикрепить метку к задаче
+// @Description Прикрепляет существующую метку к задаче. Повторное прикрепление не изменяет задачу и ее версию
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Param name path string true "Название метки"
+// @Success 200 {object} models.Task "Задача с меткой"
+// @Header 200 {string} ETag "Версия задачи"
+// @Failure 400 {object} problem.Problem "Неверный ID или название метки"
+// @Failure 404 {object} problem.Problem "Задача или метка не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/tags/{name} [put]
+func (h *Handler) AttachTag(c *fiber.Ctx) error {
+	return h.changeTag(c, "tag", h.repo.AttachTag)
+}
+
+// DetachTag открепляет метку от задачи
+// @Summary Открепить метку от задачи
+// @Description Открепляет метку от задачи. Если метка не была прикреплена, задача и ее версия не изменяются
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Param name path string true "Название метки"
+// @Success 200 {object} models.Task "Задача без метки"
+// @Header 200 {string} ETag "Версия задачи"
+// @Failure 400 {object} problem.Problem "Неверный ID или название метки"
+// @Failure 404 {object} problem.Problem "Задача или метка не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/tags/{name} [delete]
+func (h *Handler) DetachTag(c *fiber.Ctx) error {
+	return h.changeTag(c, "untag", h.repo.DetachTag)
+}
+
+func (h *Handler) changeTag(c *fiber.Ctx, action string, change func(ctx context.Context, taskID int, name string) (*models.Task, error)) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling "+action+" task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in "+action+" request", "error", err, "ip", c.IP())
+		return err
+	}
+
+	// Параметры пути ссылаются на буфер запроса, который fiber переиспользует, а название может сохраниться в хранилище
+	name, err := url.PathUnescape(strings.Clone(c.Params("name")))
+	if err != nil {
+		slog.Warn("invalid tag name in "+action+" request", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, "invalid tag name")
+	}
+	name = models.NormalizeTagName(name)
+
+	t, err := change(ctx, id, name)
+	if err != nil {
+		return storeError(c, id, action, err)
+	}
+
+	slog.Info("task tag changed successfully", "action", action, "id", id, "tag", name, "ip", c.IP())
+
+	c.Set(fiber.HeaderETag, taskETag(t))
+
+	return c.JSON(t)
+}
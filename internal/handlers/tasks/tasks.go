@@ -3,69 +3,264 @@ package tasks
 import (
 	"errors"
 	"log/slog"
-	"strconv"
 	"strings"
+	"time"
 
-	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/config"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
 	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
-var allowedToUpdate = map[string]bool{"title": true, "description": true, "status": true}
-
 type Handler struct {
-	repo *repository.TaskRepository
+	repo repository.Store
+	cfg  *config.ConfServer
 }
 
+// taskRequest схема тела запроса с данными задачи для документации, разбор выполняет decodeTaskPayload
 type taskRequest struct {
 	Title       string  `json:"title" example:"Купить молоко"`
 	Description *string `json:"description,omitempty" example:"Взять 2 литра и хлеб"`
 	Status      string  `json:"status" example:"new"`
+	// ID проекта, null - задача вне проектов
+	ProjectID *int `json:"project_id,omitempty" example:"2"`
+	// ID родительской задачи, null - задача верхнего уровня
+	ParentID *int `json:"parent_id,omitempty" example:"7"`
+	// Приоритет: low, normal, high, urgent
+	Priority string `json:"priority,omitempty" example:"high"`
+	// Срок выполнения в формате RFC 3339, null - задача без срока
+	DueAt *time.Time `json:"due_at,omitempty" example:"2025-08-20T18:00:00Z"`
+	// Правило повторения RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, UNTIL или COUNT; null - задача не повторяется
+	Recurrence *string `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
 }
 
-func NewHandler(repo *repository.TaskRepository) *Handler {
+func NewHandler(repo repository.Store, cfg *config.ConfServer) *Handler {
 	return &Handler{
 		repo: repo,
+		cfg:  cfg,
 	}
 }
 
-// List возвращает список всех задач
-// @Summary Получить список всех задач
-// @Description Возвращает список всех задач
+// List возвращает страницу списка задач
+// @Summary Получить список задач
+// @Description Возвращает страницу задач с учетом фильтров и сортировки (по умолчанию - по дате создания).
+// @Description По умолчанию используется пагинация по курсору (limit, cursor); при указании page или per_page - постраничная.
+// @Description Общее количество задач возвращается в заголовке X-Total-Count, курсор следующей страницы - в X-Next-Cursor,
+// @Description ссылки на соседние страницы - в заголовке Link
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param status query []string false "Статус задачи, можно указать несколько" collectionFormat(multi)
+// @Param created_after query string false "Создана не раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param created_before query string false "Создана раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param updated_after query string false "Обновлена не раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param updated_before query string false "Обновлена раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param project_id query int false "ID проекта"
+// @Param series_id query int false "ID первой задачи серии повторений: возвращает ее и все ее повторения"
+// @Param q query string false "Подстрока заголовка или описания"
+// @Param priority query []string false "Приоритет задачи, можно указать несколько" collectionFormat(multi) Enums(low, normal, high, urgent)
+// @Param due_after query string false "Срок не раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param due_before query string false "Срок раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param tag query []string false "Название метки, можно указать несколько" collectionFormat(multi)
+// @Param tag_mode query string false "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)" Enums(any, all)
+// @Param sort query string false "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания" example(-updated_at,title)
+// @Param limit query int false "Размер страницы в режиме курсора"
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor предыдущей страницы"
+// @Param page query int false "Номер страницы в постраничном режиме, начиная с 1"
+// @Param per_page query int false "Размер страницы в постраничном режиме"
 // @Success 200 {array} models.Task "Список задач"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Header 200 {integer} X-Total-Count "Общее количество задач"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Header 200 {string} Link "Ссылки на соседние страницы"
+// @Header 200 {string} ETag "Слабый ETag страницы"
+// @Param If-None-Match header string false "ETag ранее полученной страницы"
+// @Success 304 "Страница не изменилась"
+// @Failure 400 {object} problem.Problem "Неверные параметры фильтрации, сортировки или пагинации"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
 // @Router /tasks [get]
 func (h *Handler) List(c *fiber.Ctx) error {
+	if slog.Default().Enabled(c.Context(), slog.LevelDebug) {
+		slog.Debug("handling list tasks request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	return h.list(c, nil)
+}
+
+// list возвращает страницу задач по параметрам запроса. scope, если задан, дополняет параметры
+// условиями самого маршрута, например выбирает задачи из корзины или задает сортировку по умолчанию
+func (h *Handler) list(c *fiber.Ctx, scope func(p *repository.ListParams)) error {
 	ctx := c.Context()
 
-	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("handling list tasks request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	p, err := h.parsePagination(c)
+	if err != nil {
+		slog.Warn("invalid pagination parameters", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, err.Error())
+	}
+
+	p.params.Filter, err = parseFilter(c)
+	if err != nil {
+		slog.Warn("invalid filter parameters", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, err.Error())
 	}
 
-	tasks, err := h.repo.List(c)
+	p.params.Sort, err = parseSort(c)
+	if err != nil {
+		slog.Warn("invalid sort parameter", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, err.Error())
+	}
+
+	if scope != nil {
+		scope(&p.params)
+	}
+
+	if p.params.After != nil && !p.params.After.Matches(p.params.Sort) {
+		slog.Warn("cursor does not match sort", "sort", c.Query("sort"), "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, "cursor was issued for a different sort")
+	}
+
+	res, err := h.repo.List(ctx, p.params)
 	if err != nil {
 		slog.Error("failed to list tasks", "error", err, "ip", c.IP())
-		return jsonError(c, fiber.StatusInternalServerError, err.Error())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to list tasks")
+	}
+
+	p.setHeaders(c, res.Total, res.Next)
+
+	etag := listETag(res.Tasks, res.Total)
+	c.Set(fiber.HeaderETag, etag)
+
+	if notModified(c, etag) {
+		slog.Info("tasks not modified", "ip", c.IP())
+		return c.SendStatus(fiber.StatusNotModified)
 	}
 
-	slog.Info("tasks listed successfully", "count", len(tasks), "ip", c.IP())
+	slog.Info("tasks listed successfully", "count", len(res.Tasks), "total", res.Total, "ip", c.IP())
+
+	return c.JSON(res.Tasks)
+}
+
+// Search выполняет полнотекстовый поиск по заголовкам и описаниям задач
+// @Summary Полнотекстовый поиск задач
+// @Description Ищет задачи по заголовку и описанию, упорядочивая результаты по релевантности.
+// @Description Запрос поддерживает фразы в кавычках, "or", исключение слов через "-" и префиксы вида "прогр*".
+// @Description Найденные слова в полях highlight выделены тегом <mark>. Доступно только для хранилища postgres
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param q query string true "Поисковый запрос" example("купить молоко" хлеб*)
+// @Param page query int false "Номер страницы, начиная с 1"
+// @Param per_page query int false "Размер страницы"
+// @Success 200 {array} models.TaskSearchResult "Найденные задачи"
+// @Header 200 {integer} X-Total-Count "Общее количество найденных задач"
+// @Header 200 {string} Link "Ссылки на соседние страницы"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Failure 501 {object} problem.Problem "Поиск не поддерживается хранилищем"
+// @Router /tasks/search [get]
+func (h *Handler) Search(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling search tasks request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	searcher, ok := h.repo.(repository.TaskSearcher)
+	if !ok {
+		slog.Warn("full-text search is not supported by storage", "ip", c.IP())
+		return problem.New(fiber.StatusNotImplemented, problem.CodeNotImplemented, "full-text search is not supported by the storage driver")
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		slog.Warn("search rejected: empty query", "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, "q is required")
+	}
+
+	p, err := h.parsePages(c)
+	if err != nil {
+		slog.Warn("invalid pagination parameters", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidParameter, err.Error())
+	}
+
+	res, err := searcher.Search(ctx, repository.SearchParams{Query: q, Limit: p.params.Limit, Offset: p.params.Offset})
+	if err != nil {
+		slog.Error("failed to search tasks", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to search tasks")
+	}
+
+	p.setHeaders(c, res.Total, nil)
+
+	slog.Info("tasks searched successfully", "count", len(res.Tasks), "total", res.Total, "ip", c.IP())
 
-	return c.JSON(tasks)
+	return c.JSON(res.Tasks)
+}
+
+// Get возвращает задачу по ID
+// @Summary Получить задачу
+// @Description Возвращает задачу по указанному ID вместе с активными задачами, которые ее блокируют и которые блокирует она
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Param If-None-Match header string false "ETag ранее полученной версии задачи"
+// @Success 200 {object} models.TaskDetail "Задача"
+// @Header 200 {string} ETag "Версия задачи"
+// @Success 304 "Задача не изменилась"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id} [get]
+func (h *Handler) Get(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling get task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in get request", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid id")
+	}
+
+	t, err := h.repo.Get(ctx, id)
+	if err != nil {
+		return storeError(c, id, "get", err)
+	}
+
+	etag := taskETag(t)
+	c.Set(fiber.HeaderETag, etag)
+
+	if notModified(c, etag) {
+		slog.Info("task not modified", "id", id, "ip", c.IP())
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	detail, err := h.detail(ctx, t)
+	if err != nil {
+		return storeError(c, id, "get", err)
+	}
+
+	slog.Info("task fetched successfully", "id", id, "ip", c.IP())
+
+	return c.JSON(detail)
 }
 
 // Create создает новую задачу
 // @Summary Создать новую задачу
-// @Description Создает новую задачу с указанными параметрами
+// @Description Создает новую задачу с указанными параметрами. Задачу нельзя создать в архивном проекте.
+// @Description С parent_id задача создается как подзадача активной задачи, с recurrence - как первая задача серии повторений
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param task body taskRequest true "Данные задачи (title, description, status)"
+// @Param task body taskRequest true "Данные задачи (title, description, status, project_id, parent_id, priority, due_at, recurrence)"
 // @Success 200 {object} models.Task "Созданная задача"
-// @Failure 400 {object} map[string]string "Неверный запрос"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 409 {object} problem.Problem "Проект в архиве"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
 // @Router /tasks [post]
 func (h *Handler) Create(c *fiber.Ctx) error {
 	ctx := c.Context()
@@ -74,41 +269,51 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		slog.Debug("handling create task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
 	}
 
-	task := &models.Task{}
-	if err := c.BodyParser(task); err != nil {
-		slog.Warn("failed to parse request body", "error", err, "ip", c.IP())
-		return jsonError(c, fiber.StatusBadRequest, "invalid request")
-	}
-
-	if strings.TrimSpace(task.Title) == "" {
-		slog.Warn("task creation rejected: empty title", "ip", c.IP())
-		return jsonError(c, fiber.StatusBadRequest, "title is required")
+	p, err := decodeTaskPayload(c.Body(), false)
+	if err != nil {
+		slog.Warn("task creation rejected", "error", err, "ip", c.IP())
+		return problem.Payload(err)
 	}
+	task := p.task()
 
 	slog.Info("creating task", "title", task.Title, "status", task.Status, "ip", c.IP())
 
-	if err := h.repo.Create(c, task); err != nil {
-		slog.Error("failed to create task in database", "error", err, "title", task.Title, "ip", c.IP())
-		return jsonError(c, fiber.StatusInternalServerError, "failed to create task")
+	if err := h.repo.Create(ctx, task); err != nil {
+		return storeError(c, 0, "create", err)
 	}
 
 	slog.Info("task created successfully", "id", task.ID, "title", task.Title, "ip", c.IP())
 
+	c.Set(fiber.HeaderETag, taskETag(task))
+
 	return c.JSON(task)
 }
 
-// Update обновляет существующую задачу
-// @Summary Обновить задачу
-// @Description Обновляет существующую задачу по ID
+// Update заменяет существующую задачу
+// @Summary Заменить задачу
+// @Description Полностью заменяет задачу по ID: title обязателен, отсутствующее описание очищается,
+// @Description отсутствующий статус сбрасывается в new, приоритет - в normal, отсутствующий project_id убирает задачу
+// @Description из проекта, отсутствующий parent_id делает задачу задачей верхнего уровня, отсутствующий due_at снимает срок,
+// @Description отсутствующий recurrence отключает повторение.
+// @Description Задачу нельзя переместить в архивный проект или сделать подзадачей ее собственной подзадачи.
+// @Description Статус меняется только по переходам, которые допускает процесс (GET /statuses).
+// @Description Перевод задачи в статус категории done выполняет все ее подзадачи, создает следующее повторение повторяющейся задачи и запрещен, пока задачу или ее подзадачи блокируют
+// @Description невыполненные задачи, если не передан ignore_blockers=true. Для частичного изменения используйте PATCH
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path int true "ID задачи"
-// @Param task body taskRequest true "Данные задачи (title, description, status)"
+// @Param task body taskRequest true "Данные задачи (title, description, status, project_id, parent_id, priority, due_at, recurrence)"
+// @Param ignore_blockers query bool false "Выполнить задачу без проверки блокирующих задач"
+// @Param If-Match header string false "ETag версии задачи, которую изменяет клиент"
 // @Success 200 {object} models.Task "Обновленная задача"
-// @Failure 400 {object} map[string]string "Неверный запрос"
-// @Failure 404 {object} map[string]string "Задача не найдена"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Header 200 {string} ETag "Новая версия задачи"
+// @Failure 400 {object} problem.Problem "Неверный запрос"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 409 {object} problem.Problem "Проект в архиве, процесс не допускает переход в новый статус или задачу блокируют невыполненные задачи"
+// @Failure 422 {object} problem.Problem "Ошибки проверки полей"
+// @Failure 412 {object} problem.Problem "Задача была изменена другим клиентом"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
 // @Router /tasks/{id} [put]
 func (h *Handler) Update(c *fiber.Ctx) error {
 	ctx := c.Context()
@@ -117,72 +322,73 @@ func (h *Handler) Update(c *fiber.Ctx) error {
 		slog.Debug("handling update task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
 	}
 
-	id, err := parseID(c)
+	id, err := params.ID(c)
 	if err != nil {
 		slog.Warn("invalid task ID in update request", "error", err, "ip", c.IP())
 		return err
 	}
 
-	updates := map[string]any{}
-	if err := c.BodyParser(&updates); err != nil {
-		slog.Warn("failed to parse update request body", "error", err, "task_id", id, "ip", c.IP())
-		return jsonError(c, fiber.StatusBadRequest, "invalid request")
+	p, err := decodeTaskPayload(c.Body(), false)
+	if err != nil {
+		slog.Warn("update rejected", "error", err, "task_id", id, "ip", c.IP())
+		return problem.Payload(err)
 	}
 
-	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("updating task", "id", id, "updates", updates, "ip", c.IP())
-	} else {
-		slog.Info("updating task", "id", id, "ip", c.IP())
+	opts, err := updateOptions(c)
+	if err != nil {
+		return err
 	}
 
-	for k := range updates {
-		if !allowedToUpdate[k] {
-			delete(updates, k)
-		}
+	// PUT заменяет задачу целиком: отсутствующее описание очищается, статус сбрасывается в статус по умолчанию
+	replacement := p.task()
+	updates := map[string]any{
+		"title":       replacement.Title,
+		"description": replacement.Description,
+		"status":      replacement.Status,
+		"project_id":  replacement.ProjectID,
+		"parent_id":   replacement.ParentID,
+		"priority":    replacement.Priority,
+		"due_at":      replacement.DueAt,
+		"recurrence":  replacement.Recurrence,
 	}
 
-	if title, ok := updates["title"]; ok {
-		str, ok := title.(string)
-		if !ok || strings.TrimSpace(str) == "" {
-			slog.Warn("update rejected: empty title", "task_id", id, "ip", c.IP())
-			return jsonError(c, fiber.StatusBadRequest, "title cannot be empty")
-		}
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("replacing task", "id", id, "updates", updates, "ip", c.IP())
+	} else {
+		slog.Info("replacing task", "id", id, "ip", c.IP())
 	}
 
-	if status, ok := updates["status"]; ok {
-		str, ok := status.(string)
-		if !ok || !isValidStatus(str) {
-			slog.Warn("update rejected: invalid status", "task_id", id, "status", status, "ip", c.IP())
-			return jsonError(c, fiber.StatusBadRequest, "invalid status")
-		}
+	version, err := h.expectedVersion(ctx, c, id)
+	if err != nil {
+		return storeError(c, id, "update", err)
 	}
 
-	t, err := h.repo.Update(c, id, updates)
+	t, err := h.repo.Update(ctx, id, updates, version, opts)
 	if err != nil {
-		if errors.Is(err, fiber.ErrNotFound) {
-			slog.Warn("task not found for update", "task_id", id, "ip", c.IP())
-			return jsonError(c, fiber.StatusNotFound, "task not found")
-		}
-		slog.Error("failed to update task in database", "error", err, "task_id", id, "ip", c.IP())
-		return jsonError(c, fiber.StatusInternalServerError, "failed to update task")
+		return storeError(c, id, "update", err)
 	}
 
-	slog.Info("task updated successfully", "id", id, "ip", c.IP())
+	slog.Info("task updated successfully", "id", id, "version", t.Version, "ip", c.IP())
+
+	c.Set(fiber.HeaderETag, taskETag(t))
 
 	return c.JSON(t)
 }
 
 // Delete удаляет задачу по ID
-// @Summary Удалить задачу
-// @Description Удаляет задачу по указанному ID
+// @Summary Удалить задачу в корзину
+// @Description Перемещает задачу в корзину вместе со всеми подзадачами. Задачу можно восстановить
+// @Description через POST /tasks/{id}/restore, пока она не удалена из корзины вручную или по истечении срока хранения
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path int true "ID задачи"
-// @Success 204 "Задача успешно удалена"
-// @Failure 400 {object} map[string]string "Неверный ID"
-// @Failure 404 {object} map[string]string "Задача не найдена"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Param If-Match header string false "ETag версии задачи, которую удаляет клиент"
+// @Success 204 "Задача перемещена в корзину"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача не найдена"
+// @Failure 412 {object} problem.Problem "Задача была изменена другим клиентом"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
 // @Router /tasks/{id} [delete]
 func (h *Handler) Delete(c *fiber.Ctx) error {
 	ctx := c.Context()
@@ -191,7 +397,7 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 		slog.Debug("handling delete task request", "ip", c.IP(), "user_agent", "User-Agent")
 	}
 
-	id, err := parseID(c)
+	id, err := params.ID(c)
 	if err != nil {
 		slog.Warn("invalid task ID in delete request", "error", err, "ip", c.IP())
 		return err
@@ -199,13 +405,13 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 
 	slog.Info("deleting task", "id", id, "ip", c.IP())
 
-	if err := h.repo.Delete(c, id); err != nil {
-		if errors.Is(err, fiber.ErrNotFound) {
-			slog.Warn("task not found for deletion", "task_id", id, "ip", c.IP())
-			return jsonError(c, fiber.StatusNotFound, "task not found")
-		}
-		slog.Error("failed to delete task from database", "error", err, "task_id", id, "ip", c.IP())
-		return jsonError(c, fiber.StatusInternalServerError, "failed to delete task")
+	version, err := h.expectedVersion(ctx, c, id)
+	if err != nil {
+		return storeError(c, id, "delete", err)
+	}
+
+	if err := h.repo.Delete(ctx, id, version); err != nil {
+		return storeError(c, id, "delete", err)
 	}
 
 	slog.Info("task deleted successfully", "id", id, "ip", c.IP())
@@ -213,19 +419,62 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
-func parseID(c *fiber.Ctx) (int, error) {
-	id, err := strconv.Atoi(c.Params("id"))
-	if err != nil || id <= 0 {
-		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid id")
+// storeProblem преобразует ошибку хранилища в ошибку API. Для неизвестных ошибок возвращает nil
+func storeProblem(err error) *problem.Problem {
+	switch {
+	case errors.Is(err, repository.ErrProjectNotFound):
+		return problem.Validation(validation.Errors{
+			{Field: "project_id", Code: validation.CodeNotFound, Message: "project not found"},
+		})
+	case errors.Is(err, repository.ErrParentNotFound):
+		return problem.Validation(validation.Errors{
+			{Field: "parent_id", Code: validation.CodeNotFound, Message: "parent task not found"},
+		})
+	case errors.Is(err, repository.ErrParentCycle):
+		return problem.Validation(validation.Errors{
+			{Field: "parent_id", Code: validation.CodeCycle, Message: "parent task must not be the task itself or its subtask"},
+		})
+	case errors.Is(err, repository.ErrParentDeleted):
+		return problem.New(fiber.StatusConflict, problem.CodeParentDeleted, "parent task is deleted, restore it first")
+	case errors.Is(err, repository.ErrProjectArchived):
+		return problem.New(fiber.StatusConflict, problem.CodeProjectArchived, "project is archived")
+	case errors.Is(err, repository.ErrBlockerNotFound):
+		return problem.New(fiber.StatusNotFound, problem.CodeTaskNotFound, "blocker task not found")
+	case errors.Is(err, repository.ErrDependencyCycle):
+		return problem.New(fiber.StatusConflict, problem.CodeDependencyCycle, "blocker task must not be the task itself or a task it blocks")
+	case errors.Is(err, repository.ErrStatusNotFound):
+		return problem.Validation(validation.Errors{
+			{Field: "status", Code: validation.CodeNotFound, Message: "status not found"},
+		})
+	case errors.Is(err, repository.ErrStatusTransition):
+		return problem.New(fiber.StatusConflict, problem.CodeInvalidTransition, "workflow does not allow this status transition")
+	case errors.Is(err, repository.ErrTaskBlocked):
+		return problem.New(fiber.StatusConflict, problem.CodeTaskBlocked, "task has open blockers, complete them first or pass ignore_blockers=true")
+	case errors.Is(err, repository.ErrTagNotFound):
+		return problem.New(fiber.StatusNotFound, problem.CodeTagNotFound, "tag not found")
+	case errors.Is(err, repository.ErrNotFound):
+		return problem.New(fiber.StatusNotFound, problem.CodeTaskNotFound, "task not found")
+	case errors.Is(err, errPreconditionFailed), errors.Is(err, repository.ErrVersionMismatch):
+		return problem.New(fiber.StatusPreconditionFailed, problem.CodeVersionMismatch, "task was modified, fetch the latest version and retry")
+	case errors.Is(err, repository.ErrConflict):
+		return problem.New(fiber.StatusConflict, problem.CodeConflict, "task conflicts with existing data")
+	case errors.Is(err, repository.ErrInvalid):
+		return problem.New(fiber.StatusUnprocessableEntity, problem.CodeConstraintViolation, "task violates storage constraints")
+	default:
+		return nil
 	}
-
-	return id, nil
 }
 
-func jsonError(c *fiber.Ctx, status int, msg string) error {
-	return c.Status(status).JSON(fiber.Map{"error": msg})
-}
+// storeError логирует ошибку хранилища при выполнении action над задачей и возвращает ответ на нее.
+// Неизвестные ошибки скрываются за 500
+func storeError(c *fiber.Ctx, id int, action string, err error) error {
+	p := storeProblem(err)
+	if p == nil {
+		slog.Error("failed to "+action+" task", "error", err, "task_id", id, "ip", c.IP())
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "failed to "+action+" task")
+	}
+
+	slog.Warn(action+" task rejected", "error", err, "code", p.Code, "task_id", id, "if_match", c.Get(fiber.HeaderIfMatch), "ip", c.IP())
 
-func isValidStatus(s string) bool {
-	return s == "new" || s == "in_progress" || s == "done"
+	return p
 }
@@ -0,0 +1,53 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestUpdateStatusTransition(t *testing.T) {
+	store := repository.NewMemoryTaskRepository()
+	err := store.UpdateStatus(context.Background(), &models.Status{
+		Name:        models.DefaultTaskStatus,
+		Category:    models.TodoStatusCategory,
+		Transitions: []string{"in_progress"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	app := newStoreTestApp(t, store)
+	if resp, body := do(t, app, fiber.MethodPost, "/tasks", `{"title":"a"}`); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("create: status = %d: %s", resp.StatusCode, body)
+	}
+
+	tests := []struct {
+		name   string
+		status string
+		want   int
+		code   problem.Code
+	}{
+		{"transition not allowed", models.DoneTaskStatus, fiber.StatusConflict, problem.CodeInvalidTransition},
+		{"allowed transition", "in_progress", fiber.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := do(t, app, fiber.MethodPut, "/tasks/1", `{"title":"a","status":"`+tt.status+`"}`)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.want, body)
+			}
+
+			if tt.code != "" {
+				if p := decodeProblem(t, body); p.Code != tt.code {
+					t.Errorf("code = %s, want %s", p.Code, tt.code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package tasks
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/params"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Trash возвращает страницу задач из корзины
+// @Summary Получить корзину
+// @Description Возвращает страницу удаленных задач. Поддерживает те же параметры фильтрации, сортировки
+// @Description и пагинации, что и GET /tasks. Задачи хранятся в корзине в течение TRASH_RETENTION
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param status query []string false "Статус задачи, можно указать несколько" collectionFormat(multi)
+// @Param q query string false "Подстрока заголовка или описания"
+// @Param priority query []string false "Приоритет задачи, можно указать несколько" collectionFormat(multi) Enums(low, normal, high, urgent)
+// @Param due_after query string false "Срок не раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param due_before query string false "Срок раньше (RFC 3339 или YYYY-MM-DD)"
+// @Param tag query []string false "Название метки, можно указать несколько" collectionFormat(multi)
+// @Param tag_mode query string false "Режим фильтра по меткам: any (любая из меток, по умолчанию) или all (все метки)" Enums(any, all)
+// @Param sort query string false "Сортировка: поля id, title, status, priority, due_at, created_at, updated_at через запятую, префикс - для убывания"
+// @Param limit query int false "Размер страницы в режиме курсора"
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor предыдущей страницы"
+// @Param page query int false "Номер страницы в постраничном режиме, начиная с 1"
+// @Param per_page query int false "Размер страницы в постраничном режиме"
+// @Success 200 {array} models.Task "Список удаленных задач"
+// @Header 200 {integer} X-Total-Count "Общее количество задач в корзине"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Header 200 {string} Link "Ссылки на соседние страницы"
+// @Failure 400 {object} problem.Problem "Неверные параметры фильтрации, сортировки или пагинации"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /trash [get]
+func (h *Handler) Trash(c *fiber.Ctx) error {
+	if slog.Default().Enabled(c.Context(), slog.LevelDebug) {
+		slog.Debug("handling list trash request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	return h.list(c, func(p *repository.ListParams) {
+		p.Filter.Deleted = true
+	})
+}
+
+// Restore возвращает задачу из корзины
+// @Summary Восстановить задачу
+// @Description Возвращает удаленную задачу из корзины в список задач вместе с подзадачами, удаленными одновременно с ней.
+// @Description Подзадачу нельзя восстановить, пока ее родительская задача находится в корзине
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Success 200 {object} models.Task "Восстановленная задача"
+// @Header 200 {string} ETag "Новая версия задачи"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача не найдена в корзине"
+// @Failure 409 {object} problem.Problem "Родительская задача находится в корзине"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /tasks/{id}/restore [post]
+func (h *Handler) Restore(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling restore task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in restore request", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid id")
+	}
+
+	t, err := h.repo.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			slog.Warn("task not found in trash", "task_id", id, "ip", c.IP())
+			return problem.New(fiber.StatusNotFound, problem.CodeTaskNotFound, "task not found in trash")
+		}
+		return storeError(c, id, "restore", err)
+	}
+
+	slog.Info("task restored successfully", "id", id, "ip", c.IP())
+
+	c.Set(fiber.HeaderETag, taskETag(t))
+
+	return c.JSON(t)
+}
+
+// Purge безвозвратно удаляет задачу из корзины
+// @Summary Удалить задачу из корзины
+// @Description Безвозвратно удаляет задачу, находящуюся в корзине, вместе со всеми подзадачами
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Success 204 "Задача удалена безвозвратно"
+// @Failure 400 {object} problem.Problem "Неверный ID"
+// @Failure 404 {object} problem.Problem "Задача не найдена в корзине"
+// @Failure 500 {object} problem.Problem "Внутренняя ошибка сервера"
+// @Router /trash/{id} [delete]
+func (h *Handler) Purge(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("handling purge task request", "ip", c.IP(), "user_agent", c.Get("User-Agent"))
+	}
+
+	id, err := params.ID(c)
+	if err != nil {
+		slog.Warn("invalid task ID in purge request", "error", err, "ip", c.IP())
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidID, "invalid id")
+	}
+
+	if err := h.repo.Purge(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			slog.Warn("task not found in trash", "task_id", id, "ip", c.IP())
+			return problem.New(fiber.StatusNotFound, problem.CodeTaskNotFound, "task not found in trash")
+		}
+		return storeError(c, id, "purge", err)
+	}
+
+	slog.Info("task purged successfully", "id", id, "ip", c.IP())
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
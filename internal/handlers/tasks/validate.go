@@ -0,0 +1,159 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/recurrence"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/validation"
+)
+
+const (
+	maxTitleLength       = 200
+	maxDescriptionLength = 5000
+)
+
+// taskPayload данные задачи из тела запроса
+type taskPayload struct {
+	Title       string
+	Description *string
+	Status      string
+	ProjectID   *int
+	ParentID    *int
+	Priority    string
+	DueAt       *time.Time
+	Recurrence  *string
+	// present поля, переданные в запросе
+	present map[string]bool
+}
+
+// decodeTaskPayload разбирает и проверяет данные задачи. При partial проверяются только переданные поля,
+// иначе title обязателен. Ошибки полей возвращаются как validation.Errors
+func decodeTaskPayload(body []byte, partial bool) (*taskPayload, error) {
+	p := &taskPayload{}
+	v := &validation.Validator{}
+
+	present, err := v.DecodeObject(body, map[string]any{
+		"title":       &p.Title,
+		"description": &p.Description,
+		"status":      &p.Status,
+		"project_id":  &p.ProjectID,
+		"parent_id":   &p.ParentID,
+		"priority":    &p.Priority,
+		"due_at":      &p.DueAt,
+		"recurrence":  &p.Recurrence,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.present = present
+
+	// правила проверяются только для полей, значения которых имеют верный тип
+	if (!partial || present["title"]) && !v.Failed("title") {
+		v.Required("title", p.Title)
+		v.MaxLength("title", p.Title, maxTitleLength)
+	}
+
+	if p.Description != nil && !v.Failed("description") {
+		v.MaxLength("description", *p.Description, maxDescriptionLength)
+	}
+
+	if (p.Status != "" || (partial && present["status"])) && !v.Failed("status") {
+		v.MaxLength("status", p.Status, models.MaxStatusNameLength)
+		v.Matches("status", p.Status, models.StatusNamePattern, "be a status name of lowercase latin letters, digits and '_'")
+	}
+
+	if p.ProjectID != nil && !v.Failed("project_id") {
+		v.Positive("project_id", *p.ProjectID)
+	}
+
+	if p.ParentID != nil && !v.Failed("parent_id") {
+		v.Positive("parent_id", *p.ParentID)
+	}
+
+	if (p.Priority != "" || (partial && present["priority"])) && !v.Failed("priority") {
+		v.OneOf("priority", p.Priority, models.TaskPriorities)
+	}
+
+	if p.DueAt != nil {
+		dueAt := p.DueAt.UTC()
+		p.DueAt = &dueAt
+	}
+
+	if p.Recurrence != nil && !v.Failed("recurrence") {
+		p.Recurrence = validateRecurrence(v, *p.Recurrence)
+	}
+
+	return p, v.Err()
+}
+
+// task возвращает задачу с данными запроса; пустые статус и приоритет заменяются значениями по умолчанию
+func (p *taskPayload) task() *models.Task {
+	t := &models.Task{
+		Title:      p.Title,
+		Status:     p.Status,
+		ProjectID:  p.ProjectID,
+		ParentID:   p.ParentID,
+		Priority:   p.Priority,
+		DueAt:      p.DueAt,
+		Recurrence: p.Recurrence,
+	}
+	if p.Description != nil {
+		t.Description = *p.Description
+	}
+	if t.Status == "" {
+		t.Status = models.DefaultTaskStatus
+	}
+	if t.Priority == "" {
+		t.Priority = models.DefaultTaskPriority
+	}
+
+	return t
+}
+
+// updates возвращает переданные в запросе поля для частичного обновления
+func (p *taskPayload) updates() map[string]any {
+	updates := map[string]any{}
+	if p.present["title"] {
+		updates["title"] = p.Title
+	}
+	if p.present["description"] {
+		updates["description"] = ""
+		if p.Description != nil {
+			updates["description"] = *p.Description
+		}
+	}
+	if p.present["status"] {
+		updates["status"] = p.Status
+	}
+	if p.present["project_id"] {
+		updates["project_id"] = p.ProjectID
+	}
+	if p.present["parent_id"] {
+		updates["parent_id"] = p.ParentID
+	}
+	if p.present["priority"] {
+		updates["priority"] = p.Priority
+	}
+	if p.present["due_at"] {
+		updates["due_at"] = p.DueAt
+	}
+	if p.present["recurrence"] {
+		updates["recurrence"] = p.Recurrence
+	}
+
+	return updates
+}
+
+// validateRecurrence проверяет правило повторения и возвращает его в каноническом виде
+func validateRecurrence(v *validation.Validator, rule string) *string {
+	r, err := recurrence.Parse(rule)
+	if err != nil {
+		v.Add("recurrence", validation.CodeInvalidFormat, "recurrence must be a valid RRULE: "+err.Error())
+		return nil
+	}
+
+	canonical := r.String()
+
+	return &canonical
+}
@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCreateValidationProblem(t *testing.T) {
+	app := newTestApp(t)
+
+	description := strings.Repeat("x", maxDescriptionLength+1)
+	body := `{"title":"","description":"` + description + `","priority":"asap","project_id":"2","color":"red"}`
+
+	resp, raw := do(t, app, fiber.MethodPost, "/tasks", body)
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d: %s", resp.StatusCode, fiber.StatusUnprocessableEntity, raw)
+	}
+	if ct := resp.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(ct, problem.ContentType) {
+		t.Errorf("content type = %q, want %q", ct, problem.ContentType)
+	}
+
+	// ответ разбирается в map, чтобы проверить точные имена полей problem+json
+	var p map[string]any
+	if err := json.Unmarshal(raw, &p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	want := map[string]any{
+		"type":   "urn:rest-todo-list:problem:validation_failed",
+		"title":  "Unprocessable Entity",
+		"status": float64(fiber.StatusUnprocessableEntity),
+		"code":   string(problem.CodeValidationFailed),
+	}
+	for k, v := range want {
+		if p[k] != v {
+			t.Errorf("%s = %v, want %v", k, p[k], v)
+		}
+	}
+
+	items, ok := p["errors"].([]any)
+	if !ok {
+		t.Fatalf("errors = %v, want an array", p["errors"])
+	}
+
+	got := []string{}
+	for _, item := range items {
+		fe, ok := item.(map[string]any)
+		if !ok || len(fe) != 3 || fe["field"] == nil || fe["code"] == nil || fe["message"] == "" {
+			t.Errorf("error item %v, want field, code and message", item)
+			continue
+		}
+		got = append(got, fe["field"].(string)+":"+fe["code"].(string))
+	}
+	slices.Sort(got)
+
+	wantErrs := []string{
+		"color:" + validation.CodeUnknownField,
+		"description:" + validation.CodeTooLong,
+		"priority:" + validation.CodeInvalidEnum,
+		"project_id:" + validation.CodeInvalidType,
+		"title:" + validation.CodeRequired,
+	}
+	if !slices.Equal(got, wantErrs) {
+		t.Errorf("errors = %v, want %v", got, wantErrs)
+	}
+}
+
+func TestCreateInvalidBody(t *testing.T) {
+	app := newTestApp(t)
+
+	for _, body := range []string{`{"title":"a"} {"title":"b"}`, `[{"title":"a"}]`, `null`, `{"title":`} {
+		t.Run(body, func(t *testing.T) {
+			resp, raw := do(t, app, fiber.MethodPost, "/tasks", body)
+			if resp.StatusCode != fiber.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, fiber.StatusBadRequest, raw)
+			}
+
+			p := decodeProblem(t, raw)
+			if p.Code != problem.CodeInvalidBody || len(p.Errors) != 0 {
+				t.Errorf("problem = %+v, want %s without field errors", p, problem.CodeInvalidBody)
+			}
+		})
+	}
+}
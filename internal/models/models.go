@@ -1,8 +1,50 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
 
-const DefaultTaskStatus = "new"
+// DefaultTaskStatus и DoneTaskStatus встроенные статусы процесса, которые нельзя удалить
+const (
+	DefaultTaskStatus   = "new"
+	DoneTaskStatus      = "done"
+	DefaultTaskPriority = "normal"
+)
+
+// TaskPriorities приоритеты задач по возрастанию важности
+var TaskPriorities = []string{"low", "normal", "high", "urgent"}
+
+// Категории статусов: задача в статусе категории done считается выполненной
+const (
+	TodoStatusCategory  = "todo"
+	DoingStatusCategory = "doing"
+	DoneStatusCategory  = "done"
+)
+
+// StatusCategories категории статусов в порядке их прохождения задачей
+var StatusCategories = []string{TodoStatusCategory, DoingStatusCategory, DoneStatusCategory}
+
+// MaxStatusNameLength максимальная длина названия статуса
+const MaxStatusNameLength = 50
+
+// StatusNamePattern допускает строчные латинские буквы, цифры и подчеркивание, название начинается с буквы
+var StatusNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Действия над задачами, которые записываются в журнал аудита: delete перемещает задачу в корзину,
+// restore возвращает ее из корзины, purge удаляет из корзины безвозвратно
+const (
+	CreateAuditAction  = "create"
+	UpdateAuditAction  = "update"
+	DeleteAuditAction  = "delete"
+	RestoreAuditAction = "restore"
+	PurgeAuditAction   = "purge"
+)
+
+// AuditActions действия журнала аудита
+var AuditActions = []string{CreateAuditAction, UpdateAuditAction, DeleteAuditAction, RestoreAuditAction, PurgeAuditAction}
 
 // Task представляет задачу в системе
 // swagger:model Task
@@ -21,12 +63,140 @@ type Task struct {
 	// example: Взять 2 литра и хлеб
 	Description string `json:"description"`
 
-	// Статус задачи
+	// Статус задачи из настроенного процесса, см. Status
 	// required: true
-	// enum: new,in_progress,done
 	// example: new
 	Status string `json:"status"`
 
+	// ID проекта задачи, null для задач вне проектов
+	// required: false
+	// example: 2
+	ProjectID *int `json:"project_id"`
+
+	// ID родительской задачи, null для задач верхнего уровня
+	// required: false
+	// example: 7
+	ParentID *int `json:"parent_id"`
+
+	// Количество выполненных и всех активных подзадач первого уровня (только в ответе)
+	Subtasks SubtaskProgress `json:"subtasks"`
+
+	// Приоритет задачи
+	// required: false
+	// enum: low,normal,high,urgent
+	// example: high
+	Priority string `json:"priority"`
+
+	// Срок выполнения задачи, null для задач без срока
+	// required: false
+	// example: 2025-08-20T18:00:00Z
+	DueAt *time.Time `json:"due_at"`
+
+	// Правило повторения в формате RRULE, null для неповторяющихся задач
+	// required: false
+	// example: FREQ=WEEKLY;BYDAY=MO
+	Recurrence *string `json:"recurrence"`
+
+	// ID первой задачи серии повторений, null для первой задачи и неповторяющихся задач (только в ответе)
+	// example: 12
+	SeriesID *int `json:"series_id"`
+
+	// ID задачи серии, выполнение которой создало это повторение, null для задач, созданных вручную (только в ответе)
+	// example: 15
+	SpawnedFrom *int `json:"spawned_from"`
+
+	// Названия меток задачи в алфавитном порядке (только в ответе)
+	// example: ["bug","urgent"]
+	Tags []string `json:"tags"`
+
+	// Дата создания (только в ответе)
+	// example: 2025-08-13T14:52:00Z
+	CreatedAt time.Time `json:"created_at"`
+
+	// Дата последнего обновления (только в ответе)
+	// example: 2025-08-13T15:12:00Z
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Дата первого перехода в статус категории doing или done, null для задач, работа над которыми не начиналась (только в ответе)
+	// example: 2025-08-14T10:00:00Z
+	StartedAt *time.Time `json:"started_at"`
+
+	// Дата перехода в статус категории done, null для невыполненных задач (только в ответе)
+	// example: 2025-08-15T17:30:00Z
+	CompletedAt *time.Time `json:"completed_at"`
+
+	// Версия задачи, увеличивается при каждом изменении и передается в заголовке ETag (только в ответе)
+	// example: 1
+	Version int `json:"version"`
+
+	// Дата перемещения в корзину, заполнена только у удаленных задач (только в ответе)
+	// example: 2025-08-14T09:30:00Z
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// SubtaskProgress прогресс выполнения подзадач
+type SubtaskProgress struct {
+	// example: 2
+	Done int `json:"done"`
+
+	// example: 3
+	Total int `json:"total"`
+}
+
+// TaskTree задача вместе с активными подзадачами всех уровней
+// swagger:model TaskTree
+type TaskTree struct {
+	Task
+
+	// Подзадачи в порядке создания
+	Children []*TaskTree `json:"children"`
+}
+
+// TaskDetail задача вместе со связями блокировки
+// swagger:model TaskDetail
+type TaskDetail struct {
+	Task
+
+	// Активные задачи, которые блокируют задачу
+	BlockedBy []TaskRef `json:"blocked_by"`
+
+	// Активные задачи, которые блокирует задача
+	Blocks []TaskRef `json:"blocks"`
+}
+
+// TaskRef краткое представление связанной задачи
+type TaskRef struct {
+	// example: 4
+	ID int `json:"id"`
+
+	// example: Согласовать дату переезда
+	Title string `json:"title"`
+
+	// example: in_progress
+	Status string `json:"status"`
+}
+
+// Project объединяет задачи одной инициативы
+// swagger:model Project
+type Project struct {
+	// ID проекта (только в ответе)
+	// example: 2
+	ID int `json:"id"`
+
+	// Название проекта, уникально
+	// required: true
+	// example: Переезд
+	Name string `json:"name"`
+
+	// Описание проекта
+	// required: false
+	// example: Все, что нужно сделать до конца месяца
+	Description string `json:"description"`
+
+	// Дата архивации, заполнена только у архивных проектов (только в ответе)
+	// example: 2025-09-01T10:00:00Z
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
 	// Дата создания (только в ответе)
 	// example: 2025-08-13T14:52:00Z
 	CreatedAt time.Time `json:"created_at"`
@@ -35,3 +205,140 @@ type Task struct {
 	// example: 2025-08-13T15:12:00Z
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// Tag метка, которой можно пометить несколько задач
+// swagger:model Tag
+type Tag struct {
+	// ID метки (только в ответе)
+	// example: 3
+	ID int `json:"id"`
+
+	// Название метки в нижнем регистре, уникально
+	// required: true
+	// example: urgent
+	Name string `json:"name"`
+
+	// Дата создания (только в ответе)
+	// example: 2025-08-13T14:52:00Z
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Status статус процесса работы над задачами
+// swagger:model Status
+type Status struct {
+	// Название статуса, уникально
+	// required: true
+	// example: review
+	Name string `json:"name"`
+
+	// Категория статуса: todo, doing или done
+	// required: true
+	// enum: todo,doing,done
+	// example: doing
+	Category string `json:"category"`
+
+	// Позиция статуса в процессе, статусы упорядочены по ней
+	// required: false
+	// example: 3
+	Position int `json:"position"`
+
+	// Статусы, в которые можно перевести задачу из этого статуса
+	// required: false
+	// example: ["in_progress","done"]
+	Transitions []string `json:"transitions"`
+}
+
+// StatusChange запись истории статусов задачи
+// swagger:model StatusChange
+type StatusChange struct {
+	// ID записи
+	// example: 15
+	ID int `json:"id"`
+
+	// ID задачи
+	// example: 1
+	TaskID int `json:"task_id"`
+
+	// Прежний статус, null для записи о создании задачи
+	// example: new
+	From *string `json:"from"`
+
+	// Новый статус
+	// example: in_progress
+	To string `json:"to"`
+
+	// Время изменения
+	// example: 2025-08-14T10:00:00Z
+	At time.Time `json:"at"`
+
+	// Автор изменения из заголовка X-Actor, указанный клиентом и не проверенный сервером, null если автор не указан
+	// example: alice
+	Actor *string `json:"actor"`
+}
+
+// AuditEntry запись журнала аудита изменений задач
+// swagger:model AuditEntry
+type AuditEntry struct {
+	// ID записи
+	// example: 42
+	ID int `json:"id"`
+
+	// ID задачи, запись сохраняется и после удаления задачи из корзины
+	// example: 1
+	TaskID int `json:"task_id"`
+
+	// Действие над задачей
+	// enum: create,update,delete,restore,purge
+	// example: update
+	Action string `json:"action"`
+
+	// Значения измененных полей до действия, null для создания и восстановления задачи
+	// example: {"status":"new","due_at":null}
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+
+	// Значения измененных полей после действия, null для удаления задачи в корзину и из корзины
+	// example: {"status":"in_progress","due_at":"2025-08-20T18:00:00Z"}
+	After json.RawMessage `json:"after" swaggertype:"object"`
+
+	// Автор изменения из заголовка X-Actor, указанный клиентом и не проверенный сервером, null если автор не указан
+	// example: alice
+	Actor *string `json:"actor"`
+
+	// Идентификатор запроса из заголовка X-Request-ID
+	// example: 0b5c3f8e-6a1d-4d4e-9c1a-2f7b8e9d0a11
+	RequestID *string `json:"request_id"`
+
+	// IP адрес клиента
+	// example: 203.0.113.7
+	IP *string `json:"ip"`
+
+	// Время изменения
+	// example: 2025-08-14T10:00:00Z
+	At time.Time `json:"at"`
+}
+
+// TaskSearchResult задача, найденная полнотекстовым поиском
+// swagger:model TaskSearchResult
+type TaskSearchResult struct {
+	Task
+
+	// Релевантность задачи запросу, чем больше, тем выше
+	// example: 0.6
+	Rank float64 `json:"rank"`
+
+	// Фрагменты заголовка и описания с найденными словами, выделенными тегом <mark>
+	Highlight TaskHighlight `json:"highlight"`
+}
+
+type TaskHighlight struct {
+	// example: Купить <mark>молоко</mark>
+	Title string `json:"title"`
+
+	// example: Взять 2 литра и хлеб
+	Description string `json:"description"`
+}
+
+// NormalizeTagName приводит название метки к каноническому виду: без пробелов по краям и в нижнем регистре
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
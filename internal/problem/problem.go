@@ -0,0 +1,118 @@
+// Package problem описывает ошибки API в формате RFC 7807 (application/problem+json)
+// со стабильными машиночитаемыми кодами
+package problem
+
+import (
+	"errors"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const ContentType = "application/problem+json"
+
+// typePrefix префикс URI типа ошибки, к которому добавляется код
+const typePrefix = "urn:rest-todo-list:problem:"
+
+// Code стабильный код ошибки, по которому клиенты определяют ее причину.
+// Коды не меняются при изменении текста ошибки
+type Code string
+
+const (
+	CodeInvalidRequest       Code = "invalid_request"
+	CodeInvalidBody          Code = "invalid_body"
+	CodeInvalidID            Code = "invalid_id"
+	CodeInvalidParameter     Code = "invalid_parameter"
+	CodeInvalidPatch         Code = "invalid_patch"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeConstraintViolation  Code = "constraint_violation"
+	CodeTaskNotFound         Code = "task_not_found"
+	CodeProjectNotFound      Code = "project_not_found"
+	CodeTagNotFound          Code = "tag_not_found"
+	CodeStatusNotFound       Code = "status_not_found"
+	CodeRouteNotFound        Code = "route_not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodePatchConflict        Code = "patch_conflict"
+	CodeConflict             Code = "conflict"
+	CodeProjectArchived      Code = "project_archived"
+	CodeProjectNotEmpty      Code = "project_not_empty"
+	CodeParentDeleted        Code = "parent_deleted"
+	CodeDependencyCycle      Code = "dependency_cycle"
+	CodeTaskBlocked          Code = "task_blocked"
+	CodeInvalidTransition    Code = "invalid_transition"
+	CodeStatusInUse          Code = "status_in_use"
+	CodeStatusBuiltin        Code = "status_builtin"
+	CodeVersionMismatch      Code = "version_mismatch"
+	CodePayloadTooLarge      Code = "payload_too_large"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeBatchAborted         Code = "batch_aborted"
+	CodeInternal             Code = "internal_error"
+	CodeNotImplemented       Code = "not_implemented"
+)
+
+// Problem ошибка API. Реализует error, поэтому обработчики возвращают ее,
+// а в ответ ее превращает обработчик ошибок сервера
+type Problem struct {
+	Type     string `json:"type" example:"urn:rest-todo-list:problem:task_not_found"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"task not found"`
+	Instance string `json:"instance,omitempty" example:"/tasks/42"`
+	Code     Code   `json:"code" example:"task_not_found"`
+	// Ошибки отдельных полей для validation_failed
+	Errors []validation.FieldError `json:"errors,omitempty"`
+}
+
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   typePrefix + string(code),
+		Title:  utils.StatusMessage(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Validation ошибка 422 со списком ошибок полей
+func Validation(errs validation.Errors) *Problem {
+	p := New(fiber.StatusUnprocessableEntity, CodeValidationFailed, "request validation failed")
+	p.Errors = errs
+
+	return p
+}
+
+func (p *Problem) Error() string {
+	return string(p.Code) + ": " + p.Detail
+}
+
+// FromStatus возвращает код для ошибок, сформированных самим Fiber (неизвестный маршрут, слишком большое тело и т.п.)
+func FromStatus(status int) Code {
+	switch status {
+	case fiber.StatusBadRequest:
+		return CodeInvalidRequest
+	case fiber.StatusNotFound:
+		return CodeRouteNotFound
+	case fiber.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case fiber.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case fiber.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case fiber.StatusNotImplemented:
+		return CodeNotImplemented
+	default:
+		return CodeInternal
+	}
+}
+
+// Payload возвращает ошибку разбора тела запроса: 422 со списком ошибок полей
+// или 400, если тело не является JSON объектом
+func Payload(err error) *Problem {
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		return Validation(errs)
+	}
+
+	return New(fiber.StatusBadRequest, CodeInvalidBody, err.Error())
+}
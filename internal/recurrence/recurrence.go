@@ -0,0 +1,362 @@
+// Package recurrence разбирает правила повторения задач в формате RRULE (RFC 5545) и вычисляет даты повторений.
+// Поддерживается подмножество RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, UNTIL и COUNT
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency частота повторения
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+const (
+	maxInterval = 1000
+	maxCount    = 1000
+	// maxMonths ограничивает поиск повторения по месяцам: 29 февраля встречается хотя бы раз за 8 лет
+	maxMonths = 12 * 8
+
+	untilDateLayout = "20060102"
+	untilTimeLayout = "20060102T150405Z"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// WeekdayNum день недели из BYDAY. N задает номер дня в месяце для FREQ=MONTHLY: 1MO - первый понедельник,
+// -1FR - последняя пятница. При N == 0 подходит каждый такой день
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+func (w WeekdayNum) String() string {
+	for name, day := range weekdays {
+		if day == w.Day {
+			if w.N == 0 {
+				return name
+			}
+
+			return strconv.Itoa(w.N) + name
+		}
+	}
+
+	return ""
+}
+
+// Rule правило повторения. Повторения отсчитываются от даты предыдущего повторения:
+// INTERVAL задает шаг в днях, неделях (неделя начинается с понедельника) или месяцах от нее.
+// COUNT - количество оставшихся повторений вместе с текущим
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	Until    *time.Time
+	Count    int
+	// untilDate UNTIL задан датой без времени и включает весь день
+	untilDate bool
+}
+
+// Parse разбирает правило вида FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10. Префикс RRULE: необязателен
+func Parse(s string) (*Rule, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+	if s == "" {
+		return nil, errors.New("rule is empty")
+	}
+
+	r := &Rule{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate rule part %s", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if !slices.Contains([]Frequency{Daily, Weekly, Monthly}, r.Freq) {
+				err = errors.New("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			r.Interval, err = parseBounded(key, value, maxInterval)
+		case "COUNT":
+			r.Count, err = parseBounded(key, value, maxCount)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			err = r.parseByDay(value)
+		default:
+			err = fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT and UNTIL must not be used together")
+	}
+	if r.Freq != Monthly {
+		for _, w := range r.ByDay {
+			if w.N != 0 {
+				return nil, errors.New("BYDAY with a week number requires FREQ=MONTHLY")
+			}
+		}
+	}
+
+	return r, nil
+}
+
+func parseBounded(key, value string, maxValue int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > maxValue {
+		return 0, fmt.Errorf("%s must be an integer from 1 to %d", key, maxValue)
+	}
+
+	return n, nil
+}
+
+func (r *Rule) parseUntil(value string) error {
+	layout := untilTimeLayout
+	if len(value) == len(untilDateLayout) {
+		layout = untilDateLayout
+		r.untilDate = true
+	}
+
+	until, err := time.Parse(layout, value)
+	if err != nil {
+		return errors.New("UNTIL must be a date YYYYMMDD or a UTC time YYYYMMDDTHHMMSSZ")
+	}
+	r.Until = &until
+
+	return nil
+}
+
+func (r *Rule) parseByDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		name := item[max(len(item)-2, 0):]
+		day, ok := weekdays[name]
+		if !ok {
+			return fmt.Errorf("invalid BYDAY day %q", item)
+		}
+
+		w := WeekdayNum{Day: day}
+		if num := item[:len(item)-2]; num != "" {
+			n, err := strconv.Atoi(num)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return fmt.Errorf("invalid BYDAY week number %q", item)
+			}
+			w.N = n
+		}
+
+		if !slices.Contains(r.ByDay, w) {
+			r.ByDay = append(r.ByDay, w)
+		}
+	}
+
+	return nil
+}
+
+// String возвращает правило в каноническом виде: части в порядке FREQ, INTERVAL, BYDAY, UNTIL, COUNT,
+// INTERVAL=1 опускается
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, w := range r.ByDay {
+			days[i] = w.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.Until != nil {
+		layout := untilTimeLayout
+		if r.untilDate {
+			layout = untilDateLayout
+		}
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(layout))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Advance возвращает правило следующего повторения, в котором COUNT уменьшен на 1.
+// false, если текущее повторение последнее
+func (r *Rule) Advance() (*Rule, bool) {
+	next := *r
+	next.ByDay = slices.Clone(r.ByDay)
+
+	if r.Count > 0 {
+		if r.Count == 1 {
+			return nil, false
+		}
+		next.Count--
+	}
+
+	return &next, true
+}
+
+// Next возвращает первое повторение после after с тем же временем суток.
+// false, если повторений до UNTIL больше нет
+func (r *Rule) Next(after time.Time) (time.Time, bool) {
+	var (
+		next time.Time
+		ok   bool
+	)
+
+	switch r.Freq {
+	case Daily:
+		next, ok = r.nextDaily(after)
+	case Weekly:
+		next, ok = r.nextWeekly(after)
+	case Monthly:
+		next, ok = r.nextMonthly(after)
+	}
+
+	if !ok || r.Until == nil {
+		return next, ok
+	}
+
+	if r.untilDate {
+		return next, next.Before(r.Until.AddDate(0, 0, 1))
+	}
+
+	return next, !next.After(*r.Until)
+}
+
+func (r *Rule) nextDaily(after time.Time) (time.Time, bool) {
+	// дни недели повторяются с периодом 7 шагов
+	for k := 1; k <= 7; k++ {
+		next := after.AddDate(0, 0, k*r.Interval)
+		if r.matchesWeekday(next) {
+			return next, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (r *Rule) nextWeekly(after time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		return after.AddDate(0, 0, 7*r.Interval), true
+	}
+
+	start := weekStart(after)
+	for k := 1; k <= 7*r.Interval+7; k++ {
+		next := after.AddDate(0, 0, k)
+		weeks := int(weekStart(next).Sub(start).Hours()) / (24 * 7)
+
+		if weeks%r.Interval == 0 && r.matchesWeekday(next) {
+			return next, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (r *Rule) nextMonthly(after time.Time) (time.Time, bool) {
+	hour, minute, sec := after.Clock()
+
+	for k := 0; k <= maxMonths; k += r.Interval {
+		first := time.Date(after.Year(), after.Month()+time.Month(k), 1, hour, minute, sec, after.Nanosecond(), after.Location())
+
+		for _, day := range r.monthDays(first, after.Day()) {
+			if next := first.AddDate(0, 0, day-1); next.After(after) {
+				return next, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// monthDays возвращает подходящие дни месяца, начинающегося с first, по возрастанию.
+// Без BYDAY подходит день monthDay, если он есть в месяце
+func (r *Rule) monthDays(first time.Time, monthDay int) []int {
+	days := first.AddDate(0, 1, -1).Day()
+
+	if len(r.ByDay) == 0 {
+		if monthDay > days {
+			return nil
+		}
+
+		return []int{monthDay}
+	}
+
+	matched := []int{}
+	for _, w := range r.ByDay {
+		// день месяца первого дня недели w.Day
+		firstDay := 1 + (int(w.Day)-int(first.Weekday())+7)%7
+		candidates := []int{}
+		for day := firstDay; day <= days; day += 7 {
+			candidates = append(candidates, day)
+		}
+
+		switch {
+		case w.N == 0:
+			matched = append(matched, candidates...)
+		case w.N > 0 && w.N <= len(candidates):
+			matched = append(matched, candidates[w.N-1])
+		case w.N < 0 && -w.N <= len(candidates):
+			matched = append(matched, candidates[len(candidates)+w.N])
+		}
+	}
+
+	sort.Ints(matched)
+
+	return slices.Compact(matched)
+}
+
+func (r *Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool {
+		return w.Day == t.Weekday()
+	})
+}
+
+// weekStart возвращает полночь понедельника недели t в UTC
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
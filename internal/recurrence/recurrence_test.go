@@ -0,0 +1,284 @@
+package recurrence
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func mustParse(t *testing.T, s string) *Rule {
+	t.Helper()
+
+	r, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", s, err)
+	}
+
+	return r
+}
+
+func TestParseCanonical(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"rrule:freq=weekly;byday=we,mo;count=3;interval=1", "FREQ=WEEKLY;BYDAY=WE,MO;COUNT=3"},
+		{" RRULE:FREQ=WEEKLY;BYDAY=MO,MO ", "FREQ=WEEKLY;BYDAY=MO"},
+		{"COUNT=10;INTERVAL=2;FREQ=DAILY", "FREQ=DAILY;INTERVAL=2;COUNT=10"},
+		{"FREQ=MONTHLY;BYDAY=-1FR,1mo;INTERVAL=2;UNTIL=20251231", "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,1MO;UNTIL=20251231"},
+		{"FREQ=DAILY;UNTIL=20250201T080000Z", "FREQ=DAILY;UNTIL=20250201T080000Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			if got := mustParse(t, tt.rule).String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"empty", ""},
+		{"prefix only", "RRULE:"},
+		{"no value", "FREQ"},
+		{"empty value", "FREQ="},
+		{"no freq", "INTERVAL=2"},
+		{"yearly", "FREQ=YEARLY"},
+		{"hourly", "FREQ=HOURLY"},
+		{"bymonthday", "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{"bymonth", "FREQ=DAILY;BYMONTH=1"},
+		{"bysetpos", "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1"},
+		{"wkst", "FREQ=WEEKLY;WKST=SU"},
+		{"duplicate part", "FREQ=DAILY;FREQ=WEEKLY"},
+		{"zero interval", "FREQ=DAILY;INTERVAL=0"},
+		{"interval too large", "FREQ=DAILY;INTERVAL=1001"},
+		{"zero count", "FREQ=DAILY;COUNT=0"},
+		{"count too large", "FREQ=DAILY;COUNT=1001"},
+		{"count with until", "FREQ=DAILY;COUNT=2;UNTIL=20250101"},
+		{"until with dashes", "FREQ=DAILY;UNTIL=2025-01-01"},
+		{"until local time", "FREQ=DAILY;UNTIL=20250101T090000"},
+		{"unknown day", "FREQ=WEEKLY;BYDAY=XX"},
+		{"week number for weekly", "FREQ=WEEKLY;BYDAY=1MO"},
+		{"week number out of range", "FREQ=MONTHLY;BYDAY=6MO"},
+		{"zero week number", "FREQ=MONTHLY;BYDAY=0MO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r, err := Parse(tt.rule); err == nil {
+				t.Errorf("Parse(%q) = %q, want error", tt.rule, r)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// 31 января 2025 - пятница
+	friday := date(2025, time.January, 31, 9)
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		want  time.Time
+	}{
+		{"daily", "FREQ=DAILY", friday, date(2025, time.February, 1, 9)},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", friday, date(2025, time.February, 3, 9)},
+		{"daily byday", "FREQ=DAILY;BYDAY=MO,WE", friday, date(2025, time.February, 3, 9)},
+		{"daily interval byday", "FREQ=DAILY;INTERVAL=2;BYDAY=MO", friday, date(2025, time.February, 10, 9)},
+		{"daily keeps minutes", "FREQ=DAILY", time.Date(2025, time.January, 31, 23, 45, 30, 0, time.UTC), time.Date(2025, time.February, 1, 23, 45, 30, 0, time.UTC)},
+
+		{"weekly", "FREQ=WEEKLY", friday, date(2025, time.February, 7, 9)},
+		{"weekly interval", "FREQ=WEEKLY;INTERVAL=2", friday, date(2025, time.February, 14, 9)},
+		{"weekly byday next week", "FREQ=WEEKLY;BYDAY=WE,MO", friday, date(2025, time.February, 3, 9)},
+		{"weekly byday same week", "FREQ=WEEKLY;BYDAY=MO,FR", date(2025, time.February, 3, 9), date(2025, time.February, 7, 9)},
+		{"weekly sunday ends week", "FREQ=WEEKLY;BYDAY=SU", date(2025, time.February, 1, 9), date(2025, time.February, 2, 9)},
+		{"weekly interval byday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", date(2025, time.February, 3, 9), date(2025, time.February, 17, 9)},
+		{"weekly interval skips week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2025, time.February, 7, 9), date(2025, time.February, 17, 9)},
+		{"weekly interval from sunday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,MO", date(2025, time.February, 2, 9), date(2025, time.February, 10, 9)},
+
+		{"monthly", "FREQ=MONTHLY", date(2025, time.January, 15, 9), date(2025, time.February, 15, 9)},
+		{"monthly interval", "FREQ=MONTHLY;INTERVAL=3", date(2025, time.November, 15, 9), date(2026, time.February, 15, 9)},
+		{"monthly 31 skips short months", "FREQ=MONTHLY", friday, date(2025, time.March, 31, 9)},
+		{"monthly 31 skips april", "FREQ=MONTHLY", date(2025, time.March, 31, 9), date(2025, time.May, 31, 9)},
+		{"monthly 29 in leap year", "FREQ=MONTHLY", date(2024, time.January, 29, 9), date(2024, time.February, 29, 9)},
+		{"monthly 29 in common year", "FREQ=MONTHLY", date(2025, time.January, 29, 9), date(2025, time.March, 29, 9)},
+		{"monthly last friday", "FREQ=MONTHLY;BYDAY=-1FR", friday, date(2025, time.February, 28, 9)},
+		{"monthly first monday", "FREQ=MONTHLY;BYDAY=1MO", friday, date(2025, time.February, 3, 9)},
+		{"monthly fifth friday", "FREQ=MONTHLY;BYDAY=5FR", friday, date(2025, time.May, 30, 9)},
+		{"monthly every monday", "FREQ=MONTHLY;BYDAY=MO", date(2025, time.February, 24, 9), date(2025, time.March, 3, 9)},
+
+		{"until date includes day", "FREQ=DAILY;UNTIL=20250201", friday, date(2025, time.February, 1, 9)},
+		{"until time equal", "FREQ=DAILY;UNTIL=20250201T090000Z", friday, date(2025, time.February, 1, 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mustParse(t, tt.rule).Next(tt.after)
+			if !ok {
+				t.Fatalf("Next(%v) = none, want %v", tt.after, tt.want)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextExhausted(t *testing.T) {
+	friday := date(2025, time.January, 31, 9)
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+	}{
+		{"until date passed", "FREQ=DAILY;UNTIL=20250131", friday},
+		{"until time passed", "FREQ=DAILY;UNTIL=20250201T080000Z", friday},
+		{"until before next byday", "FREQ=WEEKLY;BYDAY=MO;UNTIL=20250202", friday},
+		{"until before next month end", "FREQ=MONTHLY;UNTIL=20250330", friday},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := mustParse(t, tt.rule).Next(tt.after); ok {
+				t.Errorf("Next(%v) = %v, want none", tt.after, got)
+			}
+		})
+	}
+}
+
+// series возвращает даты повторений, которые создаются при выполнении каждого повторения в срок
+func series(t *testing.T, rule string, start time.Time) []time.Time {
+	t.Helper()
+
+	r := mustParse(t, rule)
+	dates := []time.Time{}
+
+	for due := start; len(dates) <= maxCount; {
+		following, ok := r.Advance()
+		if !ok {
+			break
+		}
+
+		next, ok := r.Next(due)
+		if !ok {
+			break
+		}
+
+		dates = append(dates, next)
+		r, due = following, next
+	}
+
+	return dates
+}
+
+func TestSeriesExhaustion(t *testing.T) {
+	friday := date(2025, time.January, 31, 9)
+
+	tests := []struct {
+		name string
+		rule string
+		want []time.Time
+	}{
+		{"count includes current", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3", []time.Time{
+			date(2025, time.February, 3, 9), date(2025, time.February, 5, 9),
+		}},
+		{"count one", "FREQ=DAILY;COUNT=1", []time.Time{}},
+		{"until date", "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20250209", []time.Time{
+			date(2025, time.February, 3, 9), date(2025, time.February, 5, 9),
+		}},
+		{"until time inclusive", "FREQ=DAILY;UNTIL=20250203T090000Z", []time.Time{
+			date(2025, time.February, 1, 9), date(2025, time.February, 2, 9), date(2025, time.February, 3, 9),
+		}},
+		{"until month end", "FREQ=MONTHLY;UNTIL=20250731", []time.Time{
+			date(2025, time.March, 31, 9), date(2025, time.May, 31, 9), date(2025, time.July, 31, 9),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := series(t, tt.rule, friday); !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("series(%q) = %v, want %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+		ok   bool
+	}{
+		{"FREQ=DAILY;COUNT=3", "FREQ=DAILY;COUNT=2", true},
+		{"FREQ=DAILY;COUNT=1", "", false},
+		{"FREQ=DAILY;UNTIL=20250101", "FREQ=DAILY;UNTIL=20250101", true},
+		{"FREQ=WEEKLY;BYDAY=MO", "FREQ=WEEKLY;BYDAY=MO", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r := mustParse(t, tt.rule)
+
+			next, ok := r.Advance()
+			if ok != tt.ok {
+				t.Fatalf("Advance() ok = %v, want %v", ok, tt.ok)
+			}
+
+			if ok && next.String() != tt.want {
+				t.Errorf("Advance() = %q, want %q", next, tt.want)
+			}
+
+			if r.String() != mustParse(t, tt.rule).String() {
+				t.Errorf("Advance() changed the rule to %q", r)
+			}
+		})
+	}
+}
+
+func TestNextIgnoresDST(t *testing.T) {
+	// сроки хранятся в UTC, поэтому переход на летнее время в часовых поясах пользователей не сдвигает
+	// время повторений: 9 марта 2025 - переход в США, 30 марта - в Европе, 26 октября - обратный переход в Европе
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		step  time.Duration
+	}{
+		{"daily us spring", "FREQ=DAILY", date(2025, time.March, 8, 9), 24 * time.Hour},
+		{"daily eu spring", "FREQ=DAILY", date(2025, time.March, 29, 23), 24 * time.Hour},
+		{"daily eu autumn", "FREQ=DAILY", date(2025, time.October, 25, 23), 24 * time.Hour},
+		{"weekly eu spring", "FREQ=WEEKLY", date(2025, time.March, 27, 9), 7 * 24 * time.Hour},
+		{"weekly byday eu autumn", "FREQ=WEEKLY;BYDAY=MO", date(2025, time.October, 24, 9), 3 * 24 * time.Hour},
+		{"monthly eu spring", "FREQ=MONTHLY", date(2025, time.March, 15, 9), 31 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mustParse(t, tt.rule).Next(tt.after)
+			if !ok {
+				t.Fatalf("Next(%v) = none", tt.after)
+			}
+
+			if got.Location() != time.UTC {
+				t.Errorf("Next(%v) location = %v, want UTC", tt.after, got.Location())
+			}
+
+			if step := got.Sub(tt.after); step != tt.step {
+				t.Errorf("Next(%v) = %v, step %v, want %v", tt.after, got, step, tt.step)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+// AuditStore описывает журнал аудита изменений задач. Каждый метод, изменяющий задачи, записывает в журнал
+// в той же транзакции, что и само изменение, значения измененных полей каждой затронутой задачи до и после него
+// вместе с автором, идентификатором запроса и IP адресом клиента из requestinfo.Info контекста. Создание
+// повторения задачи записывается как создание, метки и зависимости - как изменение полей tags и blocked_by.
+// Записи журнала не изменяются и не удаляются, в том числе при удалении задачи из корзины
+type AuditStore interface {
+	// Audit возвращает записи журнала, подходящие под filter, от новых к старым
+	Audit(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error)
+}
+
+// AuditFilter условия отбора записей журнала аудита. Пустые поля не ограничивают выборку.
+// From включительная граница времени изменения, To - исключающая
+type AuditFilter struct {
+	TaskID *int
+	Actor  string
+	Action string
+	From   *time.Time
+	To     *time.Time
+	// BeforeID выбирает записи с ID меньше указанного, используется для пагинации
+	BeforeID int
+	Limit    int
+}
+
+// auditFields значения полей задачи, изменения которых записываются в журнал аудита, nil для отсутствующей задачи
+func auditFields(t *models.Task) map[string]any {
+	if t == nil {
+		return nil
+	}
+
+	tags := t.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return map[string]any{
+		"title":       t.Title,
+		"description": t.Description,
+		"status":      t.Status,
+		"project_id":  t.ProjectID,
+		"parent_id":   t.ParentID,
+		"priority":    t.Priority,
+		"due_at":      t.DueAt,
+		"recurrence":  t.Recurrence,
+		"tags":        tags,
+	}
+}
+
+// blockerAuditFields значения списка задач, блокирующих задачу, для журнала аудита
+func blockerAuditFields(blockerIDs []int) map[string]any {
+	if blockerIDs == nil {
+		blockerIDs = []int{}
+	}
+
+	return map[string]any{"blocked_by": blockerIDs}
+}
+
+// auditDiff возвращает JSON значений полей задачи from и to до и после изменения. Для создания и восстановления
+// from равен nil, для удаления to равен nil, для изменения оба содержат только отличающиеся поля.
+// ok равен false, если изменение не затронуло ни одного поля
+func auditDiff(from, to map[string]any) (beforeJSON, afterJSON []byte, ok bool, err error) {
+	if from != nil && to != nil {
+		for k, v := range from {
+			old, err := json.Marshal(v)
+			if err != nil {
+				return nil, nil, false, err
+			}
+
+			cur, err := json.Marshal(to[k])
+			if err != nil {
+				return nil, nil, false, err
+			}
+
+			if string(old) == string(cur) {
+				delete(from, k)
+				delete(to, k)
+			}
+		}
+
+		if len(from) == 0 {
+			return nil, nil, false, nil
+		}
+	}
+
+	if from != nil {
+		if beforeJSON, err = json.Marshal(from); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	if to != nil {
+		if afterJSON, err = json.Marshal(to); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	return beforeJSON, afterJSON, true, nil
+}
+
+// auditChange изменение одной задачи в действии над несколькими задачами
+type auditChange struct {
+	id            int
+	before, after *models.Task
+}
+
+// auditChanges сопоставляет состояния задач до и после действия по ID. Задача, которой нет в before или after,
+// получает nil с этой стороны. Изменения возвращаются по возрастанию ID задачи
+func auditChanges(before, after []models.Task) []auditChange {
+	byID := map[int]*auditChange{}
+	ids := []int{}
+
+	change := func(id int) *auditChange {
+		c, ok := byID[id]
+		if !ok {
+			c = &auditChange{id: id}
+			byID[id] = c
+			ids = append(ids, id)
+		}
+
+		return c
+	}
+
+	for i := range before {
+		change(before[i].ID).before = &before[i]
+	}
+	for i := range after {
+		change(after[i].ID).after = &after[i]
+	}
+
+	slices.Sort(ids)
+
+	changes := make([]auditChange, len(ids))
+	for i, id := range ids {
+		changes[i] = *byID[id]
+	}
+
+	return changes
+}
+
+// auditAction действие журнала аудита для изменения задачи из before в after, см. auditDiff
+func auditAction(before, after *models.Task) string {
+	switch {
+	case before == nil:
+		return models.CreateAuditAction
+	case after == nil:
+		return models.DeleteAuditAction
+	default:
+		return models.UpdateAuditAction
+	}
+}
+
+// auditJSON преобразует JSON для записи в базу данных: отсутствующее значение сохраняется как NULL
+func auditJSON(b []byte) any {
+	if b == nil {
+		return nil
+	}
+
+	return string(b)
+}
+
+// auditColumns колонки журнала аудита в порядке, ожидаемом scanAuditEntry.
+// JSON выбирается текстом, одинаково для JSONB в PostgreSQL и TEXT в SQLite
+const auditColumns = `id, task_id, action, CAST(before AS TEXT), CAST(after AS TEXT), actor, request_id, ip, changed_at`
+
+func scanAuditEntry(row rowScanner, e *models.AuditEntry) error {
+	var before, after *string
+
+	if err := row.Scan(&e.ID, &e.TaskID, &e.Action, &before, &after, &e.Actor, &e.RequestID, &e.IP, &e.At); err != nil {
+		return err
+	}
+
+	e.Before, e.After = rawJSON(before), rawJSON(after)
+
+	return nil
+}
+
+// rawJSON возвращает JSON null для отсутствующего значения
+func rawJSON(s *string) json.RawMessage {
+	if s == nil {
+		return json.RawMessage("null")
+	}
+
+	return json.RawMessage(*s)
+}
+
+// buildAuditQuery возвращает запрос записей журнала аудита, подходящих под f, от новых к старым
+func buildAuditQuery(f AuditFilter, d sqlDialect) (string, []any) {
+	b := &sqlBuilder{dialect: d}
+
+	if f.TaskID != nil {
+		b.where = append(b.where, "task_id = "+b.arg(*f.TaskID))
+	}
+	if f.Actor != "" {
+		b.where = append(b.where, "actor = "+b.arg(f.Actor))
+	}
+	if f.Action != "" {
+		b.where = append(b.where, "action = "+b.arg(f.Action))
+	}
+	if f.From != nil {
+		b.where = append(b.where, "changed_at >= "+b.arg(f.From.UTC()))
+	}
+	if f.To != nil {
+		b.where = append(b.where, "changed_at < "+b.arg(f.To.UTC()))
+	}
+	if f.BeforeID > 0 {
+		b.where = append(b.where, "id < "+b.arg(f.BeforeID))
+	}
+
+	query := "SELECT " + auditColumns + " FROM task_audit" + b.whereSQL() + " ORDER BY id DESC"
+
+	if f.Limit > 0 {
+		query += " LIMIT " + b.arg(f.Limit)
+	}
+
+	return query, b.args
+}
+
+func (r *TaskRepository) Audit(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: get audit log", "filter", filter)
+	}
+
+	query, args := buildAuditQuery(filter, pgDialect)
+
+	rows, err := r.dbPool.Query(ctx, query, args...)
+	if err != nil {
+		slog.Error("database query failed: get audit log", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := scanAuditEntry(rows, &e); err != nil {
+			slog.Error("failed to scan audit log row", "error", err)
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: get audit log", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: get audit log", "count", len(entries))
+	}
+
+	return entries, nil
+}
+
+// pgAudit записывает в журнал аудита изменение задачи id из before в after, см. auditDiff.
+// Изменение, не затронувшее ни одного поля, не записывается
+func pgAudit(ctx context.Context, q pgQuerier, id int, before, after *models.Task) error {
+	return pgRecordAudit(ctx, q, id, auditAction(before, after), auditFields(before), auditFields(after))
+}
+
+// pgAuditTasks записывает в журнал аудита действие action над несколькими задачами по их состояниям
+// до и после него, см. auditChanges
+func pgAuditTasks(ctx context.Context, q pgQuerier, action string, before, after []models.Task) error {
+	for _, c := range auditChanges(before, after) {
+		if err := pgRecordAudit(ctx, q, c.id, action, auditFields(c.before), auditFields(c.after)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pgRecordAudit записывает в журнал аудита действие action над задачей id со значениями полей from и to
+func pgRecordAudit(ctx context.Context, q pgQuerier, id int, action string, from, to map[string]any) error {
+	before, after, ok, err := auditDiff(from, to)
+	if err != nil {
+		slog.Error("failed to encode audit entry", "error", err, "task_id", id)
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	info := requestinfo.From(ctx)
+
+	query := `
+		INSERT INTO task_audit (task_id, action, before, after, actor, request_id, ip, changed_at)
+		VALUES ($1, $2, CAST($3 AS JSONB), CAST($4 AS JSONB), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), now())`
+
+	_, err = q.Exec(ctx, query, id, action, auditJSON(before), auditJSON(after), info.Actor, info.RequestID, info.IP)
+	if err != nil {
+		slog.Error("database query failed: record audit entry", "error", err, "task_id", id, "action", action)
+		return pgError(err)
+	}
+
+	return nil
+}
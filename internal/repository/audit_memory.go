@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+func (r *MemoryTaskRepository) Audit(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: get audit log", "filter", filter)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []models.AuditEntry{}
+	for i := len(r.audit) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+
+		if e := r.audit[i]; filter.match(&e) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
+
+func (f AuditFilter) match(e *models.AuditEntry) bool {
+	switch {
+	case f.TaskID != nil && e.TaskID != *f.TaskID:
+		return false
+	case f.Actor != "" && (e.Actor == nil || *e.Actor != f.Actor):
+		return false
+	case f.Action != "" && e.Action != f.Action:
+		return false
+	case f.From != nil && e.At.Before(*f.From):
+		return false
+	case f.To != nil && !e.At.Before(*f.To):
+		return false
+	case f.BeforeID > 0 && e.ID >= f.BeforeID:
+		return false
+	}
+
+	return true
+}
+
+// recordAudit записывает в журнал аудита изменение задачи из before в after в момент now, см. pgAudit.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) recordAudit(ctx context.Context, before, after *models.Task, now time.Time) {
+	id := 0
+	if before != nil {
+		id = before.ID
+	}
+	if after != nil {
+		id = after.ID
+	}
+
+	r.recordAuditFields(ctx, id, auditAction(before, after), auditFields(before), auditFields(after), now)
+}
+
+// recordAuditTasks записывает в журнал аудита действие action над несколькими задачами, см. pgAuditTasks.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) recordAuditTasks(ctx context.Context, action string, before, after []models.Task, now time.Time) {
+	for _, c := range auditChanges(before, after) {
+		r.recordAuditFields(ctx, c.id, action, auditFields(c.before), auditFields(c.after), now)
+	}
+}
+
+// recordAuditFields записывает в журнал аудита действие над задачей id в момент now, см. pgRecordAudit.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) recordAuditFields(ctx context.Context, id int, action string, from, to map[string]any, now time.Time) {
+	before, after, ok, err := auditDiff(from, to)
+	if err != nil {
+		slog.Error("failed to encode audit entry", "error", err, "task_id", id)
+		return
+	}
+
+	if !ok {
+		return
+	}
+
+	e := models.AuditEntry{
+		ID:     r.nextAuditID,
+		TaskID: id,
+		Action: action,
+		Before: rawJSON(nil),
+		After:  rawJSON(nil),
+		At:     now,
+	}
+
+	if before != nil {
+		e.Before = before
+	}
+	if after != nil {
+		e.After = after
+	}
+
+	info := requestinfo.From(ctx)
+	if info.Actor != "" {
+		e.Actor = &info.Actor
+	}
+	if info.RequestID != "" {
+		e.RequestID = &info.RequestID
+	}
+	if info.IP != "" {
+		e.IP = &info.IP
+	}
+
+	r.audit = append(r.audit, e)
+	r.nextAuditID++
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+func (r *SQLiteTaskRepository) Audit(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: get audit log", "filter", filter)
+	}
+
+	query, args := buildAuditQuery(filter, sqliteDialect)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: get audit log", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := scanAuditEntry(rows, &e); err != nil {
+			slog.Error("failed to scan audit log row", "error", err)
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: get audit log", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: get audit log", "count", len(entries))
+	}
+
+	return entries, nil
+}
+
+// sqliteAudit записывает в журнал аудита изменение задачи в момент at, см. pgAudit
+func sqliteAudit(ctx context.Context, q sqliteQuerier, id int, before, after *models.Task, at time.Time) error {
+	return sqliteRecordAudit(ctx, q, id, auditAction(before, after), auditFields(before), auditFields(after), at)
+}
+
+// sqliteAuditTasks записывает в журнал аудита действие action над несколькими задачами, см. pgAuditTasks
+func sqliteAuditTasks(ctx context.Context, q sqliteQuerier, action string, before, after []models.Task, at time.Time) error {
+	for _, c := range auditChanges(before, after) {
+		if err := sqliteRecordAudit(ctx, q, c.id, action, auditFields(c.before), auditFields(c.after), at); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sqliteRecordAudit записывает в журнал аудита действие над задачей в момент at, см. pgRecordAudit
+func sqliteRecordAudit(ctx context.Context, q sqliteQuerier, id int, action string, from, to map[string]any, at time.Time) error {
+	before, after, ok, err := auditDiff(from, to)
+	if err != nil {
+		slog.Error("failed to encode audit entry", "error", err, "task_id", id)
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	info := requestinfo.From(ctx)
+
+	query := `
+		INSERT INTO task_audit (task_id, action, before, after, actor, request_id, ip, changed_at)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?)`
+
+	_, err = q.ExecContext(ctx, query, id, action, auditJSON(before), auditJSON(after), info.Actor, info.RequestID, info.IP, at)
+	if err != nil {
+		slog.Error("sqlite query failed: record audit entry", "error", err, "task_id", id, "action", action)
+		return sqliteError(err)
+	}
+
+	return nil
+}
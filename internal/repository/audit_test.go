@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+// requestContext контекст с пользовательскими значениями, как у запроса fasthttp
+type requestContext struct {
+	context.Context
+	values map[any]any
+}
+
+func (c *requestContext) SetUserValue(key, value any) {
+	c.values[key] = value
+}
+
+func (c *requestContext) Value(key any) any {
+	if v, ok := c.values[key]; ok {
+		return v
+	}
+
+	return c.Context.Value(key)
+}
+
+// withRequestInfo возвращает контекст запроса со сведениями info
+func withRequestInfo(info requestinfo.Info) context.Context {
+	ctx := &requestContext{Context: context.Background(), values: map[any]any{}}
+	requestinfo.Set(ctx, info)
+
+	return ctx
+}
+
+func TestAuditMutations(t *testing.T) {
+	r := NewMemoryTaskRepository()
+
+	mutations := []struct {
+		action string
+		run    func(ctx context.Context) error
+		// before и after поля записи журнала, которые должны в ней быть, nil означает null
+		before map[string]any
+		after  map[string]any
+	}{
+		{
+			action: models.CreateAuditAction,
+			run:    func(ctx context.Context) error { return r.Create(ctx, &models.Task{Title: "a"}) },
+			after:  map[string]any{"title": "a", "status": models.DefaultTaskStatus},
+		},
+		{
+			action: models.UpdateAuditAction,
+			run: func(ctx context.Context) error {
+				_, err := r.Update(ctx, 1, map[string]any{"title": "b"}, 0, UpdateOptions{})
+				return err
+			},
+			before: map[string]any{"title": "a"},
+			after:  map[string]any{"title": "b"},
+		},
+		{
+			action: models.DeleteAuditAction,
+			run:    func(ctx context.Context) error { return r.Delete(ctx, 1, 0) },
+			before: map[string]any{"title": "b"},
+		},
+		{
+			action: models.RestoreAuditAction,
+			run: func(ctx context.Context) error {
+				_, err := r.Restore(ctx, 1)
+				return err
+			},
+			after: map[string]any{"title": "b"},
+		},
+		{
+			action: models.DeleteAuditAction,
+			run:    func(ctx context.Context) error { return r.Delete(ctx, 1, 0) },
+			before: map[string]any{"title": "b"},
+		},
+		{
+			action: models.PurgeAuditAction,
+			run:    func(ctx context.Context) error { return r.Purge(ctx, 1) },
+			before: map[string]any{"title": "b"},
+		},
+	}
+
+	for i, m := range mutations {
+		t.Run(m.action, func(t *testing.T) {
+			info := requestinfo.Info{
+				Actor:     fmt.Sprintf("actor-%d", i),
+				RequestID: fmt.Sprintf("request-%d", i),
+				IP:        fmt.Sprintf("203.0.113.%d", i),
+			}
+
+			before := len(mustAudit(t, r))
+			if err := m.run(withRequestInfo(info)); err != nil {
+				t.Fatalf("%s error = %v", m.action, err)
+			}
+
+			entries := mustAudit(t, r)
+			if len(entries) != before+1 {
+				t.Fatalf("len(audit) = %d, want %d", len(entries), before+1)
+			}
+
+			e := entries[0]
+			if e.TaskID != 1 || e.Action != m.action {
+				t.Errorf("entry = task %d %s, want task 1 %s", e.TaskID, e.Action, m.action)
+			}
+
+			got := requestinfo.Info{Actor: deref(e.Actor), RequestID: deref(e.RequestID), IP: deref(e.IP)}
+			if got != info {
+				t.Errorf("request info = %+v, want %+v", got, info)
+			}
+
+			checkAuditFields(t, "before", e.Before, m.before)
+			checkAuditFields(t, "after", e.After, m.after)
+		})
+	}
+}
+
+// deref возвращает значение s или пустую строку для nil
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// checkAuditFields проверяет, что JSON raw равен null при пустом want или содержит поля want
+func checkAuditFields(t *testing.T, name string, raw json.RawMessage, want map[string]any) {
+	t.Helper()
+
+	var got map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("decode %s %s: %v", name, raw, err)
+	}
+
+	if want == nil {
+		if got != nil {
+			t.Errorf("%s = %s, want null", name, raw)
+		}
+		return
+	}
+
+	for k, v := range want {
+		if !reflect.DeepEqual(got[k], v) {
+			t.Errorf("%s[%s] = %v, want %v", name, k, got[k], v)
+		}
+	}
+}
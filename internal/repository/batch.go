@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// ErrBatchAborted возвращается для операций атомарного пакета, отмененного из-за ошибки другой операции
+var ErrBatchAborted = errors.New("batch aborted")
+
+// BatchOpKind тип операции пакетного изменения задач
+type BatchOpKind string
+
+const (
+	BatchCreate BatchOpKind = "create"
+	BatchUpdate BatchOpKind = "update"
+	BatchDelete BatchOpKind = "delete"
+)
+
+// BatchOp операция пакета. Task используется в create, Updates - в update,
+// ID и Version - в update и delete с той же семантикой, что и в TaskStore.
+// IgnoreBlockers отключает проверку блокирующих задач в update, см. UpdateOptions
+type BatchOp struct {
+	Kind           BatchOpKind
+	ID             int
+	Task           *models.Task
+	Updates        map[string]any
+	Version        int
+	IgnoreBlockers bool
+}
+
+// BatchResult результат операции пакета: созданная или измененная задача либо ошибка.
+// Для delete Task не заполняется
+type BatchResult struct {
+	Task *models.Task
+	Err  error
+}
+
+// taskWriter изменяющие операции хранилища, выполняемые внутри транзакции пакета. Они изменяют только задачи,
+// историю статусов и журнал аудита: метки, зависимости, проекты и статусы в пакете не меняются, поэтому снимок
+// для отката атомарного пакета в MemoryTaskRepository.Batch содержит только их. Операция, затрагивающая
+// что-то еще, должна расширить и этот снимок
+type taskWriter interface {
+	create(ctx context.Context, task *models.Task) error
+	update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error)
+	delete(ctx context.Context, id int, version int) error
+}
+
+func applyBatchOp(ctx context.Context, w taskWriter, op BatchOp) BatchResult {
+	switch op.Kind {
+	case BatchCreate:
+		task := *op.Task
+		if err := w.create(ctx, &task); err != nil {
+			return BatchResult{Err: err}
+		}
+
+		return BatchResult{Task: &task}
+	case BatchUpdate:
+		t, err := w.update(ctx, op.ID, op.Updates, op.Version, UpdateOptions{IgnoreBlockers: op.IgnoreBlockers})
+		return BatchResult{Task: t, Err: err}
+	case BatchDelete:
+		return BatchResult{Err: w.delete(ctx, op.ID, op.Version)}
+	default:
+		return BatchResult{Err: fmt.Errorf("unknown batch operation %q", op.Kind)}
+	}
+}
+
+// abortBatch помечает все операции, кроме неудавшейся, как отмененные
+func abortBatch(results []BatchResult, failed int) {
+	for i := range results {
+		if i != failed {
+			results[i] = BatchResult{Err: ErrBatchAborted}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// batchState видимое через хранилище состояние, которое откатывает атомарный пакет
+type batchState struct {
+	active  []models.Task
+	deleted []models.Task
+	history map[int][]models.StatusChange
+	audit   []models.AuditEntry
+}
+
+func snapshotBatchState(t *testing.T, r *MemoryTaskRepository, ids ...int) batchState {
+	t.Helper()
+
+	ctx := context.Background()
+	state := batchState{history: map[int][]models.StatusChange{}, audit: mustAudit(t, r)}
+
+	for _, deleted := range []bool{false, true} {
+		res, err := r.List(ctx, ListParams{Filter: TaskFilter{Deleted: deleted}})
+		if err != nil {
+			t.Fatalf("List(deleted=%v) error = %v", deleted, err)
+		}
+
+		if deleted {
+			state.deleted = res.Tasks
+		} else {
+			state.active = res.Tasks
+		}
+	}
+
+	for _, id := range ids {
+		history, err := r.History(ctx, id)
+		if err != nil {
+			t.Fatalf("History(%d) error = %v", id, err)
+		}
+		state.history[id] = history
+	}
+
+	return state
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		atomic bool
+		ops    []BatchOp
+		want   []error
+	}{
+		{
+			name:   "atomic success",
+			atomic: true,
+			ops: []BatchOp{
+				{Kind: BatchCreate, Task: &models.Task{Title: "c"}},
+				{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"status": "in_progress"}, Version: 1},
+				{Kind: BatchDelete, ID: 2},
+			},
+			want: []error{nil, nil, nil},
+		},
+		{
+			name:   "atomic failing middle op",
+			atomic: true,
+			ops: []BatchOp{
+				{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"status": "in_progress"}},
+				{Kind: BatchUpdate, ID: 99, Updates: map[string]any{"title": "x"}},
+				{Kind: BatchCreate, Task: &models.Task{Title: "c"}},
+			},
+			want: []error{ErrBatchAborted, ErrNotFound, ErrBatchAborted},
+		},
+		{
+			name:   "atomic version mismatch after delete",
+			atomic: true,
+			ops: []BatchOp{
+				{Kind: BatchDelete, ID: 2},
+				{Kind: BatchCreate, Task: &models.Task{Title: "c"}},
+				{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"title": "x"}, Version: 5},
+				{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"title": "y"}},
+			},
+			want: []error{ErrBatchAborted, ErrBatchAborted, ErrVersionMismatch, ErrBatchAborted},
+		},
+		{
+			name: "best effort failing middle op",
+			ops: []BatchOp{
+				{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"status": "in_progress"}},
+				{Kind: BatchUpdate, ID: 99, Updates: map[string]any{"title": "x"}},
+				{Kind: BatchCreate, Task: &models.Task{Title: "c"}},
+				{Kind: BatchDelete, ID: 2, Version: 5},
+				{Kind: BatchDelete, ID: 2},
+			},
+			want: []error{nil, ErrNotFound, nil, ErrVersionMismatch, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMemoryTaskRepository()
+			mustCreate(t, r, "a")
+			mustCreate(t, r, "b")
+			before := snapshotBatchState(t, r, 1, 2)
+
+			results, err := r.Batch(context.Background(), tt.ops, tt.atomic)
+			if err != nil {
+				t.Fatalf("Batch() error = %v", err)
+			}
+
+			if len(results) != len(tt.ops) {
+				t.Fatalf("len(results) = %d, want %d", len(results), len(tt.ops))
+			}
+
+			failed := false
+			for i, res := range results {
+				if !errors.Is(res.Err, tt.want[i]) || (res.Err == nil) != (tt.want[i] == nil) {
+					t.Errorf("results[%d].Err = %v, want %v", i, res.Err, tt.want[i])
+				}
+
+				if tt.want[i] != nil {
+					failed = true
+					continue
+				}
+
+				if tt.ops[i].Kind != BatchDelete && res.Task == nil {
+					t.Errorf("results[%d].Task = nil, want the %s task", i, tt.ops[i].Kind)
+				}
+			}
+
+			after := snapshotBatchState(t, r, 1, 2)
+
+			if tt.atomic && failed {
+				if !reflect.DeepEqual(after, before) {
+					t.Errorf("state after aborted batch = %+v, want %+v", after, before)
+				}
+
+				// откат возвращает и счетчик ID, поэтому следующая задача получает тот же ID
+				if task := mustCreate(t, r, "next"); task.ID != 3 {
+					t.Errorf("next task ID = %d, want 3", task.ID)
+				}
+
+				return
+			}
+
+			if reflect.DeepEqual(after.audit, before.audit) {
+				t.Errorf("audit unchanged after applied batch")
+			}
+		})
+	}
+}
+
+func TestBatchBestEffortApplied(t *testing.T) {
+	r := NewMemoryTaskRepository()
+	mustCreate(t, r, "a")
+
+	ops := []BatchOp{
+		{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"title": "b"}},
+		{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"title": "c"}, Version: 1},
+		{Kind: BatchUpdate, ID: 1, Updates: map[string]any{"title": "d"}, Version: 2},
+	}
+
+	results, err := r.Batch(context.Background(), ops, false)
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+
+	if !errors.Is(results[1].Err, ErrVersionMismatch) {
+		t.Errorf("results[1].Err = %v, want %v", results[1].Err, ErrVersionMismatch)
+	}
+
+	// операции после неудавшейся выполняются с учетом уже примененных
+	if got := mustGet(t, r, 1); got.Title != "d" || got.Version != 3 {
+		t.Errorf("task = %q version %d, want %q version 3", got.Title, got.Version, "d")
+	}
+
+	if got := len(mustAudit(t, r)); got != 3 {
+		t.Errorf("len(audit) = %d, want 3", got)
+	}
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrBlockerNotFound возвращается, когда блокирующая задача не существует или находится в корзине
+	ErrBlockerNotFound = fmt.Errorf("%w: blocker task not found", ErrNotFound)
+	// ErrDependencyCycle возвращается, когда задачу блокируют ею самой или задачей, которую она уже блокирует
+	ErrDependencyCycle = fmt.Errorf("%w: dependency cycle", ErrConflict)
+	// ErrTaskBlocked возвращается при переводе в done задачи, которую блокируют невыполненные задачи
+	ErrTaskBlocked = fmt.Errorf("%w: task has open blockers", ErrConflict)
+)
+
+// DependencyStore описывает связи блокировки между задачами.
+// Задачу нельзя перевести в done, пока ее блокируют невыполненные активные задачи: TaskStore возвращает
+// ErrTaskBlocked, если проверка не отключена через UpdateOptions.IgnoreBlockers. Вместе с задачей проверяются подзадачи,
+// которые будут выполнены вместе с ней. Связи задач из корзины сохраняются, но не учитываются.
+// Изменение связи, названия, статуса или наличия в корзине задачи увеличивает версию связанных с ней задач
+type DependencyStore interface {
+	// Dependencies возвращает активные задачи, которые блокируют задачу id, и активные задачи, которые блокирует она
+	Dependencies(ctx context.Context, id int) (blockedBy, blocks []models.TaskRef, err error)
+	// AddBlocker делает blockerID блокирующей задачей для taskID. Повторное добавление не изменяет задачу
+	AddBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error)
+	// RemoveBlocker удаляет связь блокировки. Если связи не было, задача не изменяется
+	RemoveBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error)
+}
+
+// dependencyRefsQuery выбирает связанные задачи: сначала блокирующие задачу, затем блокируемые ею
+const dependencyRefsQuery = `
+	SELECT true, tasks.id, tasks.title, tasks.status
+	FROM task_dependencies JOIN tasks ON tasks.id = task_dependencies.blocker_id
+	WHERE task_dependencies.task_id = %[1]s AND tasks.deleted_at IS NULL
+	UNION ALL
+	SELECT false, tasks.id, tasks.title, tasks.status
+	FROM task_dependencies JOIN tasks ON tasks.id = task_dependencies.task_id
+	WHERE task_dependencies.blocker_id = %[1]s AND tasks.deleted_at IS NULL
+	ORDER BY 2`
+
+// scanDependencyRefs разбирает строки dependencyRefsQuery
+func scanDependencyRefs(rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}) (blockedBy, blocks []models.TaskRef, err error) {
+	blockedBy, blocks = []models.TaskRef{}, []models.TaskRef{}
+
+	for rows.Next() {
+		var (
+			blocker bool
+			ref     models.TaskRef
+		)
+		if err := rows.Scan(&blocker, &ref.ID, &ref.Title, &ref.Status); err != nil {
+			return nil, nil, err
+		}
+
+		if blocker {
+			blockedBy = append(blockedBy, ref)
+		} else {
+			blocks = append(blocks, ref)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return blockedBy, blocks, nil
+}
+
+// dependencyChainQuery проверяет, блокирует ли задача %[2]s задачу %[1]s напрямую или через другие задачи
+const dependencyChainQuery = `
+	WITH RECURSIVE chain(id) AS (
+		SELECT blocker_id FROM task_dependencies WHERE task_id = %[1]s
+		UNION
+		SELECT task_dependencies.blocker_id FROM task_dependencies JOIN chain ON task_dependencies.task_id = chain.id
+	)
+	SELECT EXISTS (SELECT 1 FROM chain WHERE id = %[2]s)`
+
+// openBlockersQuery проверяет, есть ли у невыполненной задачи %[1]s или ее активных подзадач невыполненные
+// блокирующие задачи вне этого поддерева. Выполненная задача и ее подзадачи не проверяются
+var openBlockersQuery = subtreeCTE("id = %[1]s AND status NOT IN "+doneStatuses, "child.deleted_at IS NULL") + `
+	SELECT EXISTS (
+		SELECT 1
+		FROM task_dependencies
+		JOIN tasks t ON t.id = task_dependencies.task_id
+		JOIN tasks blocker ON blocker.id = task_dependencies.blocker_id
+		WHERE task_dependencies.task_id IN (SELECT id FROM subtree) AND t.status NOT IN ` + doneStatuses + `
+			AND blocker.deleted_at IS NULL AND blocker.status NOT IN ` + doneStatuses + `
+			AND blocker.id NOT IN (SELECT id FROM subtree)
+	)`
+
+func (r *TaskRepository) Dependencies(ctx context.Context, id int) ([]models.TaskRef, []models.TaskRef, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: get task dependencies", "id", id)
+	}
+
+	rows, err := r.dbPool.Query(ctx, fmt.Sprintf(dependencyRefsQuery, "$1"), id)
+	if err != nil {
+		slog.Error("database query failed: get task dependencies", "error", err, "task_id", id)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	blockedBy, blocks, err := scanDependencyRefs(rows)
+	if err != nil {
+		slog.Error("database query failed: get task dependencies", "error", err, "task_id", id)
+		return nil, nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: get task dependencies", "id", id, "blocked_by", len(blockedBy), "blocks", len(blocks))
+	}
+
+	return blockedBy, blocks, nil
+}
+
+func (r *TaskRepository) AddBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error) {
+	return r.changeDependency(ctx, taskID, blockerID, true)
+}
+
+func (r *TaskRepository) RemoveBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error) {
+	return r.changeDependency(ctx, taskID, blockerID, false)
+}
+
+// changeDependency добавляет или удаляет связь блокировки и, если связь изменилась, увеличивает версию задачи
+// и активной блокирующей задачи
+func (r *TaskRepository) changeDependency(ctx context.Context, taskID, blockerID int, add bool) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: change task dependency", "task_id", taskID, "blocker_id", blockerID, "add", add)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: change task dependency", "error", err, "task_id", taskID)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists int
+	if err := tx.QueryRow(ctx, `SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, taskID).Scan(&exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("task not found for dependency change", "task_id", taskID)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: change task dependency", "error", err, "task_id", taskID)
+
+		return nil, err
+	}
+
+	blockers := `SELECT blocker_id FROM task_dependencies WHERE task_id = $1 ORDER BY blocker_id`
+
+	before, err := pgQueryIDs(ctx, tx, blockers, taskID)
+	if err != nil {
+		slog.Error("database query failed: get task blockers", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	change := `DELETE FROM task_dependencies WHERE task_id = $1 AND blocker_id = $2`
+	if add {
+		if err := pgCheckBlocker(ctx, tx, taskID, blockerID); err != nil {
+			return nil, err
+		}
+
+		change = `INSERT INTO task_dependencies (task_id, blocker_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+
+	cmd, err := tx.Exec(ctx, change, taskID, blockerID)
+	if err != nil {
+		slog.Error("database query failed: change task dependency", "error", err, "task_id", taskID, "blocker_id", blockerID)
+		return nil, pgError(err)
+	}
+
+	if cmd.RowsAffected() > 0 {
+		// связь входит в представление обеих задач
+		query := `UPDATE tasks SET updated_at = now(), version = version + 1 WHERE id IN ($1, $2) AND deleted_at IS NULL`
+		if _, err := tx.Exec(ctx, query, taskID, blockerID); err != nil {
+			slog.Error("database query failed: change task dependency", "error", err, "task_id", taskID)
+			return nil, err
+		}
+
+		after, err := pgQueryIDs(ctx, tx, blockers, taskID)
+		if err != nil {
+			slog.Error("database query failed: get task blockers", "error", err, "task_id", taskID)
+			return nil, err
+		}
+
+		err = pgRecordAudit(ctx, tx, taskID, models.UpdateAuditAction, blockerAuditFields(before), blockerAuditFields(after))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := &models.Task{}
+	if err := scanTask(tx.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1`, taskID), t); err != nil {
+		slog.Error("database query failed: change task dependency", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: change task dependency", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// pgCheckBlocker проверяет, что blockerID может блокировать taskID: блокирующая задача активна,
+// не совпадает с taskID и не заблокирована ею напрямую или через другие задачи
+func pgCheckBlocker(ctx context.Context, q pgQuerier, taskID, blockerID int) error {
+	var exists int
+	if err := q.QueryRow(ctx, `SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, blockerID).Scan(&exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("blocker task not found", "task_id", taskID, "blocker_id", blockerID)
+			return ErrBlockerNotFound
+		}
+
+		slog.Error("database query failed: check blocker task", "error", err, "blocker_id", blockerID)
+
+		return err
+	}
+
+	cycle := taskID == blockerID
+	if !cycle {
+		if err := q.QueryRow(ctx, fmt.Sprintf(dependencyChainQuery, "$1", "$2"), blockerID, taskID).Scan(&cycle); err != nil {
+			slog.Error("database query failed: check dependency cycle", "error", err, "task_id", taskID, "blocker_id", blockerID)
+			return err
+		}
+	}
+
+	if cycle {
+		slog.Warn("dependency cycle", "task_id", taskID, "blocker_id", blockerID)
+		return ErrDependencyCycle
+	}
+
+	return nil
+}
+
+// pgCheckOpenBlockers возвращает ErrTaskBlocked, если задачу id нельзя перевести в done из-за невыполненных
+// блокирующих задач. Вызывается до изменения статуса задачи
+func pgCheckOpenBlockers(ctx context.Context, q pgQuerier, id int) error {
+	var blocked bool
+	if err := q.QueryRow(ctx, fmt.Sprintf(openBlockersQuery, "$1"), id).Scan(&blocked); err != nil {
+		slog.Error("database query failed: check open blockers", "error", err, "task_id", id)
+		return err
+	}
+
+	if blocked {
+		slog.Warn("task has open blockers", "task_id", id)
+		return ErrTaskBlocked
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// dependency связь блокировки: задачу task блокирует задача blocker
+type dependency struct {
+	task    int
+	blocker int
+}
+
+func (r *MemoryTaskRepository) Dependencies(ctx context.Context, id int) ([]models.TaskRef, []models.TaskRef, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: get task dependencies", "id", id)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	blockedBy, blocks := []models.TaskRef{}, []models.TaskRef{}
+
+	for d := range r.dependencies {
+		if d.task == id {
+			if t, ok := r.activeTask(d.blocker); ok {
+				blockedBy = append(blockedBy, taskRef(t))
+			}
+		}
+
+		if d.blocker == id {
+			if t, ok := r.activeTask(d.task); ok {
+				blocks = append(blocks, taskRef(t))
+			}
+		}
+	}
+
+	sortTaskRefs(blockedBy)
+	sortTaskRefs(blocks)
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: get task dependencies", "id", id, "blocked_by", len(blockedBy), "blocks", len(blocks))
+	}
+
+	return blockedBy, blocks, nil
+}
+
+func taskRef(t models.Task) models.TaskRef {
+	return models.TaskRef{ID: t.ID, Title: t.Title, Status: t.Status}
+}
+
+func sortTaskRefs(refs []models.TaskRef) {
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].ID < refs[j].ID
+	})
+}
+
+func (r *MemoryTaskRepository) AddBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error) {
+	return r.changeDependency(ctx, taskID, blockerID, true)
+}
+
+func (r *MemoryTaskRepository) RemoveBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error) {
+	return r.changeDependency(ctx, taskID, blockerID, false)
+}
+
+// changeDependency добавляет или удаляет связь блокировки, см. TaskRepository.changeDependency
+func (r *MemoryTaskRepository) changeDependency(ctx context.Context, taskID, blockerID int, add bool) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: change task dependency", "task_id", taskID, "blocker_id", blockerID, "add", add)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.activeTask(taskID)
+	if !ok {
+		slog.Warn("task not found for dependency change", "task_id", taskID)
+		return nil, ErrNotFound
+	}
+
+	if add {
+		if err := r.checkBlocker(taskID, blockerID); err != nil {
+			return nil, err
+		}
+	}
+
+	d := dependency{task: taskID, blocker: blockerID}
+	if r.dependencies[d] == add {
+		return &t, nil
+	}
+
+	before := r.blockerIDs(taskID)
+	if add {
+		r.dependencies[d] = true
+	} else {
+		delete(r.dependencies, d)
+	}
+
+	// связь входит в представление обеих задач
+	now := time.Now().UTC()
+	for _, id := range []int{taskID, blockerID} {
+		if changed, ok := r.activeTask(id); ok {
+			changed.UpdatedAt = now
+			changed.Version++
+			r.tasks[id] = changed
+		}
+	}
+	t = r.tasks[taskID]
+
+	r.recordAuditFields(ctx, taskID, models.UpdateAuditAction, blockerAuditFields(before), blockerAuditFields(r.blockerIDs(taskID)), now)
+
+	return &t, nil
+}
+
+// blockerIDs возвращает ID задач, блокирующих задачу taskID, по возрастанию. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) blockerIDs(taskID int) []int {
+	ids := []int{}
+	for d := range r.dependencies {
+		if d.task == taskID {
+			ids = append(ids, d.blocker)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// checkBlocker проверяет, что blockerID может блокировать taskID, см. pgCheckBlocker.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) checkBlocker(taskID, blockerID int) error {
+	if _, ok := r.activeTask(blockerID); !ok {
+		slog.Warn("blocker task not found", "task_id", taskID, "blocker_id", blockerID)
+		return ErrBlockerNotFound
+	}
+
+	blockers := map[int][]int{}
+	for d := range r.dependencies {
+		blockers[d.task] = append(blockers[d.task], d.blocker)
+	}
+
+	seen := map[int]bool{blockerID: true}
+	for queue := []int{blockerID}; len(queue) > 0; queue = queue[1:] {
+		if queue[0] == taskID {
+			slog.Warn("dependency cycle", "task_id", taskID, "blocker_id", blockerID)
+			return ErrDependencyCycle
+		}
+
+		for _, id := range blockers[queue[0]] {
+			if !seen[id] {
+				seen[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkOpenBlockers возвращает ErrTaskBlocked, если задачу id нельзя перевести в done, см. pgCheckOpenBlockers.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) checkOpenBlockers(ctx context.Context, id int) error {
+	if r.isDone(r.tasks[id].Status) {
+		return nil
+	}
+
+	inSubtree := map[int]bool{}
+	for _, taskID := range r.subtree([]int{id}, isActiveTask) {
+		inSubtree[taskID] = true
+	}
+
+	for d := range r.dependencies {
+		if !inSubtree[d.task] || inSubtree[d.blocker] || r.isDone(r.tasks[d.task].Status) {
+			continue
+		}
+
+		if blocker, ok := r.activeTask(d.blocker); ok && !r.isDone(blocker.Status) {
+			slog.Warn("task has open blockers", "task_id", id)
+			return ErrTaskBlocked
+		}
+	}
+
+	return nil
+}
+
+// dropDependencies удаляет связи безвозвратно удаленных задач, как ON DELETE CASCADE в базах данных.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) dropDependencies() {
+	for d := range r.dependencies {
+		_, taskExists := r.tasks[d.task]
+		_, blockerExists := r.tasks[d.blocker]
+
+		if !taskExists || !blockerExists {
+			delete(r.dependencies, d)
+		}
+	}
+}
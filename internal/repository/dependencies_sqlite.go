@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+func (r *SQLiteTaskRepository) Dependencies(ctx context.Context, id int) ([]models.TaskRef, []models.TaskRef, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: get task dependencies", "id", id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(dependencyRefsQuery, "?1"), id)
+	if err != nil {
+		slog.Error("sqlite query failed: get task dependencies", "error", err, "task_id", id)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	blockedBy, blocks, err := scanDependencyRefs(rows)
+	if err != nil {
+		slog.Error("sqlite query failed: get task dependencies", "error", err, "task_id", id)
+		return nil, nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: get task dependencies", "id", id, "blocked_by", len(blockedBy), "blocks", len(blocks))
+	}
+
+	return blockedBy, blocks, nil
+}
+
+func (r *SQLiteTaskRepository) AddBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error) {
+	return r.changeDependency(ctx, taskID, blockerID, true)
+}
+
+func (r *SQLiteTaskRepository) RemoveBlocker(ctx context.Context, taskID, blockerID int) (*models.Task, error) {
+	return r.changeDependency(ctx, taskID, blockerID, false)
+}
+
+// changeDependency добавляет или удаляет связь блокировки, см. TaskRepository.changeDependency
+func (r *SQLiteTaskRepository) changeDependency(ctx context.Context, taskID, blockerID int, add bool) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: change task dependency", "task_id", taskID, "blocker_id", blockerID, "add", add)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: change task dependency", "error", err, "task_id", taskID)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := sqliteGetTask(ctx, tx, taskID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("task not found for dependency change", "task_id", taskID)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: change task dependency", "error", err, "task_id", taskID)
+
+		return nil, err
+	}
+
+	blockers := `SELECT blocker_id FROM task_dependencies WHERE task_id = ? ORDER BY blocker_id`
+
+	before, err := sqliteQueryIDs(ctx, tx, blockers, taskID)
+	if err != nil {
+		slog.Error("sqlite query failed: get task blockers", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	change := `DELETE FROM task_dependencies WHERE task_id = ? AND blocker_id = ?`
+	if add {
+		if err := sqliteCheckBlocker(ctx, tx, taskID, blockerID); err != nil {
+			return nil, err
+		}
+
+		change = `INSERT INTO task_dependencies (task_id, blocker_id) VALUES (?, ?) ON CONFLICT DO NOTHING`
+	}
+
+	res, err := tx.ExecContext(ctx, change, taskID, blockerID)
+	if err != nil {
+		slog.Error("sqlite query failed: change task dependency", "error", err, "task_id", taskID, "blocker_id", blockerID)
+		return nil, sqliteError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: change task dependency", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	if n > 0 {
+		now := time.Now().UTC()
+
+		// связь входит в представление обеих задач
+		query := `UPDATE tasks SET updated_at = ?, version = version + 1 WHERE id IN (?, ?) AND deleted_at IS NULL`
+		if _, err := tx.ExecContext(ctx, query, now, taskID, blockerID); err != nil {
+			slog.Error("sqlite query failed: change task dependency", "error", err, "task_id", taskID)
+			return nil, err
+		}
+
+		after, err := sqliteQueryIDs(ctx, tx, blockers, taskID)
+		if err != nil {
+			slog.Error("sqlite query failed: get task blockers", "error", err, "task_id", taskID)
+			return nil, err
+		}
+
+		err = sqliteRecordAudit(ctx, tx, taskID, models.UpdateAuditAction, blockerAuditFields(before), blockerAuditFields(after), now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := sqliteGetTask(ctx, tx, taskID)
+	if err != nil {
+		slog.Error("sqlite query failed: change task dependency", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: change task dependency", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// sqliteCheckBlocker проверяет, что blockerID может блокировать taskID, см. pgCheckBlocker
+func sqliteCheckBlocker(ctx context.Context, q sqliteQuerier, taskID, blockerID int) error {
+	var exists int
+	if err := q.QueryRowContext(ctx, `SELECT 1 FROM tasks WHERE id = ? AND deleted_at IS NULL`, blockerID).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("blocker task not found", "task_id", taskID, "blocker_id", blockerID)
+			return ErrBlockerNotFound
+		}
+
+		slog.Error("sqlite query failed: check blocker task", "error", err, "blocker_id", blockerID)
+
+		return err
+	}
+
+	cycle := taskID == blockerID
+	if !cycle {
+		if err := q.QueryRowContext(ctx, fmt.Sprintf(dependencyChainQuery, "?", "?"), blockerID, taskID).Scan(&cycle); err != nil {
+			slog.Error("sqlite query failed: check dependency cycle", "error", err, "task_id", taskID, "blocker_id", blockerID)
+			return err
+		}
+	}
+
+	if cycle {
+		slog.Warn("dependency cycle", "task_id", taskID, "blocker_id", blockerID)
+		return ErrDependencyCycle
+	}
+
+	return nil
+}
+
+// sqliteCheckOpenBlockers возвращает ErrTaskBlocked, если задачу id нельзя перевести в done, см. pgCheckOpenBlockers
+func sqliteCheckOpenBlockers(ctx context.Context, q sqliteQuerier, id int) error {
+	var blocked bool
+	if err := q.QueryRowContext(ctx, fmt.Sprintf(openBlockersQuery, "?"), id).Scan(&blocked); err != nil {
+		slog.Error("sqlite query failed: check open blockers", "error", err, "task_id", id)
+		return err
+	}
+
+	if blocked {
+		slog.Warn("task has open blockers", "task_id", id)
+		return ErrTaskBlocked
+	}
+
+	return nil
+}
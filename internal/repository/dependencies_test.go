@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// mustAddBlocker делает задачу blockerID блокирующей для taskID
+func mustAddBlocker(t *testing.T, r *MemoryTaskRepository, taskID, blockerID int) {
+	t.Helper()
+
+	if _, err := r.AddBlocker(context.Background(), taskID, blockerID); err != nil {
+		t.Fatalf("AddBlocker(%d, %d) error = %v", taskID, blockerID, err)
+	}
+}
+
+func TestAddBlocker(t *testing.T) {
+	tests := []struct {
+		name    string
+		edges   [][2]int
+		task    int
+		blocker int
+		want    error
+	}{
+		{"independent tasks", nil, 1, 2, nil},
+		{"self dependency", nil, 1, 1, ErrDependencyCycle},
+		{"direct cycle", [][2]int{{1, 2}}, 2, 1, ErrDependencyCycle},
+		{"transitive cycle", [][2]int{{1, 2}, {2, 3}}, 3, 1, ErrDependencyCycle},
+		{"shortcut without cycle", [][2]int{{1, 2}, {2, 3}}, 1, 3, nil},
+		{"missing blocker", nil, 1, 99, ErrBlockerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := NewMemoryTaskRepository()
+			for _, title := range []string{"a", "b", "c"} {
+				mustCreate(t, r, title)
+			}
+			for _, e := range tt.edges {
+				mustAddBlocker(t, r, e[0], e[1])
+			}
+
+			_, err := r.AddBlocker(ctx, tt.task, tt.blocker)
+			if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+				t.Fatalf("AddBlocker(%d, %d) error = %v, want %v", tt.task, tt.blocker, err, tt.want)
+			}
+
+			blockedBy, _, err := r.Dependencies(ctx, tt.task)
+			if err != nil {
+				t.Fatalf("Dependencies(%d) error = %v", tt.task, err)
+			}
+
+			added := false
+			for _, ref := range blockedBy {
+				added = added || ref.ID == tt.blocker
+			}
+
+			if added != (tt.want == nil) {
+				t.Errorf("task %d blocked by %d = %v, want %v", tt.task, tt.blocker, added, tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestCompleteBlockedTask(t *testing.T) {
+	tests := []struct {
+		name string
+		// blocker состояние задачи 2, блокирующей задачу 1 или ее подзадачу 3
+		blocker    string
+		subtask    bool
+		modify     bool
+		ignore     bool
+		wantErr    error
+		wantStatus string
+	}{
+		{"open blocker", "open", false, false, false, ErrTaskBlocked, models.DefaultTaskStatus},
+		{"open blocker ignored", "open", false, false, true, nil, models.DoneTaskStatus},
+		{"open blocker via modify", "open", false, true, false, ErrTaskBlocked, models.DefaultTaskStatus},
+		{"open blocker ignored via modify", "open", false, true, true, nil, models.DoneTaskStatus},
+		{"done blocker", "done", false, false, false, nil, models.DoneTaskStatus},
+		{"blocker in trash", "deleted", false, false, false, nil, models.DoneTaskStatus},
+		{"open blocker of subtask", "open", true, false, false, ErrTaskBlocked, models.DefaultTaskStatus},
+		{"open blocker of subtask ignored", "open", true, false, true, nil, models.DoneTaskStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := NewMemoryTaskRepository()
+			mustCreate(t, r, "task")
+			mustCreate(t, r, "blocker")
+
+			blocked := 1
+			if tt.subtask {
+				parentID := 1
+				if err := r.Create(ctx, &models.Task{Title: "subtask", ParentID: &parentID}); err != nil {
+					t.Fatalf("Create(subtask) error = %v", err)
+				}
+				blocked = 3
+			}
+			mustAddBlocker(t, r, blocked, 2)
+
+			switch tt.blocker {
+			case "done":
+				if _, err := r.Update(ctx, 2, map[string]any{"status": models.DoneTaskStatus}, 0, UpdateOptions{}); err != nil {
+					t.Fatalf("Update(blocker) error = %v", err)
+				}
+			case "deleted":
+				if err := r.Delete(ctx, 2, 0); err != nil {
+					t.Fatalf("Delete(blocker) error = %v", err)
+				}
+			}
+
+			opts := UpdateOptions{IgnoreBlockers: tt.ignore}
+
+			var err error
+			if tt.modify {
+				_, err = r.Modify(ctx, 1, func(t *models.Task) error {
+					t.Status = models.DoneTaskStatus
+					return nil
+				}, 0, opts)
+			} else {
+				_, err = r.Update(ctx, 1, map[string]any{"status": models.DoneTaskStatus}, 0, opts)
+			}
+
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("complete task error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := mustGet(t, r, blocked).Status; got != tt.wantStatus {
+				t.Errorf("status of task %d = %q, want %q", blocked, got, tt.wantStatus)
+			}
+		})
+	}
+}
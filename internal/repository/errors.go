@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Ошибки хранилища, не зависящие от базы данных. Хранилища оборачивают в них ошибки драйверов,
+// поэтому вызывающий код проверяет их через errors.Is
+var (
+	// ErrNotFound возвращается, когда задача не найдена
+	ErrNotFound = errors.New("not found")
+	// ErrConflict возвращается, когда изменение противоречит существующим данным, например нарушает уникальность
+	ErrConflict = errors.New("conflict")
+	// ErrInvalid возвращается, когда данные не проходят ограничения хранилища
+	ErrInvalid = errors.New("invalid data")
+	// ErrVersionMismatch возвращается, когда текущая версия задачи не совпадает с ожидаемой
+	ErrVersionMismatch = errors.New("task version mismatch")
+)
+
+// Коды ошибок PostgreSQL, см. https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgCodeStringTooLong       = "22001"
+	pgCodeInvalidText         = "22P02"
+	pgCodeNotNullViolation    = "23502"
+	pgCodeForeignKeyViolation = "23503"
+	pgCodeUniqueViolation     = "23505"
+	pgCodeCheckViolation      = "23514"
+	pgCodeExclusionViolation  = "23P01"
+)
+
+// pgError преобразует ошибку pgx в ошибку хранилища. Неизвестные ошибки возвращаются как есть
+func pgError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case pgCodeUniqueViolation, pgCodeExclusionViolation:
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	case pgCodeCheckViolation, pgCodeNotNullViolation, pgCodeForeignKeyViolation, pgCodeStringTooLong, pgCodeInvalidText:
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	default:
+		return err
+	}
+}
+
+// sqliteError преобразует ошибку SQLite в ошибку хранилища. Неизвестные ошибки возвращаются как есть
+func sqliteError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	case sqlite3.SQLITE_CONSTRAINT_CHECK, sqlite3.SQLITE_CONSTRAINT_NOTNULL, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+// HistoryStore описывает историю статусов задач. TaskStore записывает в историю создание задачи и каждое
+// изменение ее статуса, в том числе подзадач, выполненных вместе с родителем, и повторений задач.
+// Автор изменения берется из requestinfo.Info контекста. Вместе с историей TaskStore заполняет
+// models.Task.StartedAt при первом переходе задачи из категории todo и models.Task.CompletedAt при переходе
+// в категорию done, а при возврате задачи из категории done очищает CompletedAt
+type HistoryStore interface {
+	// History возвращает историю статусов задачи id в порядке изменений
+	History(ctx context.Context, id int) ([]models.StatusChange, error)
+}
+
+// startedStatuses выбирает статусы задач, работа над которыми начата
+const startedStatuses = `(SELECT name FROM statuses WHERE category <> '` + models.TodoStatusCategory + `')`
+
+// statusTimesSet возвращает присваивания started_at и completed_at для UPDATE, переводящего задачи в статус status
+// в момент now. В правой части SET колонки имеют значения до изменения, поэтому при переходе между статусами
+// категории done completed_at сохраняется
+func statusTimesSet(status, now string) string {
+	return `started_at = COALESCE(started_at, CASE WHEN ` + status + ` IN ` + startedStatuses + ` THEN ` + now + ` END),
+		completed_at = CASE
+			WHEN ` + status + ` NOT IN ` + doneStatuses + ` THEN NULL
+			WHEN status IN ` + doneStatuses + ` THEN completed_at
+			ELSE ` + now + `
+		END`
+}
+
+// statusTimesValues возвращает значения started_at и completed_at для INSERT задачи в статусе status в момент now
+func statusTimesValues(status, now string) string {
+	return `CASE WHEN ` + status + ` IN ` + startedStatuses + ` THEN ` + now + ` END,
+		CASE WHEN ` + status + ` IN ` + doneStatuses + ` THEN ` + now + ` END`
+}
+
+// historyColumns колонки истории статусов в порядке, ожидаемом scanStatusChange
+const historyColumns = `id, task_id, from_status, to_status, changed_at, actor`
+
+func scanStatusChange(row rowScanner, c *models.StatusChange) error {
+	return row.Scan(&c.ID, &c.TaskID, &c.From, &c.To, &c.At, &c.Actor)
+}
+
+func (r *TaskRepository) History(ctx context.Context, id int) ([]models.StatusChange, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: get task history", "id", id)
+	}
+
+	query := `SELECT ` + historyColumns + ` FROM task_status_history WHERE task_id = $1 ORDER BY id`
+
+	rows, err := r.dbPool.Query(ctx, query, id)
+	if err != nil {
+		slog.Error("database query failed: get task history", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.StatusChange{}
+
+	for rows.Next() {
+		var c models.StatusChange
+		if err := scanStatusChange(rows, &c); err != nil {
+			slog.Error("failed to scan task history row", "error", err, "task_id", id)
+			return nil, err
+		}
+
+		history = append(history, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: get task history", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: get task history", "id", id, "count", len(history))
+	}
+
+	return history, nil
+}
+
+// pgRecordStatus добавляет в историю изменение статуса задачи id из from в to. Пустой from означает создание задачи
+func pgRecordStatus(ctx context.Context, q pgQuerier, id int, from, to string) error {
+	query := `
+		INSERT INTO task_status_history (task_id, from_status, to_status, changed_at, actor)
+		VALUES ($1, NULLIF($2, ''), $3, now(), NULLIF($4, ''))`
+
+	if _, err := q.Exec(ctx, query, id, from, to, requestinfo.From(ctx).Actor); err != nil {
+		slog.Error("database query failed: record task status", "error", err, "task_id", id, "from", from, "to", to)
+		return pgError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+func (r *MemoryTaskRepository) History(ctx context.Context, id int) ([]models.StatusChange, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: get task history", "id", id)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := []models.StatusChange{}
+	for _, c := range r.history {
+		if c.TaskID == id {
+			history = append(history, c)
+		}
+	}
+
+	return history, nil
+}
+
+// recordStatus добавляет в историю изменение статуса задачи t из from в момент now и обновляет ее started_at
+// и completed_at, см. statusTimesSet. Пустой from означает создание задачи. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) recordStatus(ctx context.Context, t *models.Task, from string, now time.Time) {
+	c := models.StatusChange{ID: r.nextHistoryID, TaskID: t.ID, To: t.Status, At: now}
+	if from != "" {
+		c.From = &from
+	}
+	if actor := requestinfo.From(ctx).Actor; actor != "" {
+		c.Actor = &actor
+	}
+
+	r.history = append(r.history, c)
+	r.nextHistoryID++
+
+	if t.StartedAt == nil && r.statuses[t.Status].Category != models.TodoStatusCategory {
+		t.StartedAt = &now
+	}
+
+	switch {
+	case !r.isDone(t.Status):
+		t.CompletedAt = nil
+	case !r.isDone(from):
+		t.CompletedAt = &now
+	}
+}
+
+// dropHistory удаляет историю безвозвратно удаленных задач, как ON DELETE CASCADE в базах данных.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) dropHistory() {
+	r.history = slices.DeleteFunc(r.history, func(c models.StatusChange) bool {
+		_, ok := r.tasks[c.TaskID]
+		return !ok
+	})
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+func (r *SQLiteTaskRepository) History(ctx context.Context, id int) ([]models.StatusChange, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: get task history", "id", id)
+	}
+
+	query := `SELECT ` + historyColumns + ` FROM task_status_history WHERE task_id = ? ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		slog.Error("sqlite query failed: get task history", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.StatusChange{}
+
+	for rows.Next() {
+		var c models.StatusChange
+		if err := scanStatusChange(rows, &c); err != nil {
+			slog.Error("failed to scan task history row", "error", err, "task_id", id)
+			return nil, err
+		}
+
+		history = append(history, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: get task history", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: get task history", "id", id, "count", len(history))
+	}
+
+	return history, nil
+}
+
+// sqliteRecordStatus добавляет в историю изменение статуса задачи в момент at, см. pgRecordStatus
+func sqliteRecordStatus(ctx context.Context, q sqliteQuerier, id int, from, to string, at time.Time) error {
+	query := `
+		INSERT INTO task_status_history (task_id, from_status, to_status, changed_at, actor)
+		VALUES (?, NULLIF(?, ''), ?, ?, NULLIF(?, ''))`
+
+	if _, err := q.ExecContext(ctx, query, id, from, to, at, requestinfo.From(ctx).Actor); err != nil {
+		slog.Error("sqlite query failed: record task status", "error", err, "task_id", id, "from", from, "to", to)
+		return sqliteError(err)
+	}
+
+	return nil
+}
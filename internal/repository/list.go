@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListParams параметры выборки списка задач.
+// Задачи упорядочены по Sort (по умолчанию по created_at) и затем по id, что дает стабильные страницы.
+// After и Offset взаимоисключающие: первый задает keyset пагинацию, второй - постраничную
+type ListParams struct {
+	Filter TaskFilter
+	Sort   []SortKey
+	Limit  int
+	Offset int
+	After  *Cursor
+}
+
+// TaskFilter условия отбора задач. Пустые поля не ограничивают выборку.
+// Границы *After включительные, *Before - исключающие
+type TaskFilter struct {
+	Statuses []string
+	// Open исключает выполненные задачи
+	Open       bool
+	Priorities []string
+	ProjectID  *int
+	// ParentID выбирает подзадачи первого уровня
+	ParentID *int
+	// SeriesID выбирает задачи серии повторений вместе с ее первой задачей
+	SeriesID *int
+	// Tags названия меток: задача должна иметь хотя бы одну из них или, при AllTags, все.
+	// Названия не должны повторяться
+	Tags          []string
+	AllTags       bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	// DueAfter и DueBefore выбирают только задачи со сроком
+	DueAfter  *time.Time
+	DueBefore *time.Time
+	// Text подстрока заголовка или описания без учета регистра
+	Text string
+	// Deleted выбирает задачи из корзины вместо активных
+	Deleted bool
+}
+
+// ListResult страница задач вместе с общим количеством подходящих задач.
+// Next заполнен, если после страницы есть еще задачи
+type ListResult struct {
+	Tasks []models.Task
+	Total int
+	Next  *Cursor
+}
+
+// Cursor позиция задачи в порядке сортировки списка
+type Cursor struct {
+	// sort полная сортировка, для которой построен курсор
+	sort string
+	// key значения ключей сортировки последней задачи страницы
+	key models.Task
+}
+
+type cursorPayload struct {
+	Sort   string          `json:"s"`
+	Values json.RawMessage `json:"v"`
+}
+
+func newCursor(t *models.Task, keys []SortKey) *Cursor {
+	return &Cursor{sort: FormatSort(keys), key: *t}
+}
+
+// Encode возвращает непрозрачное строковое представление курсора для передачи клиенту.
+// В курсор попадают только значения ключей сортировки
+func (c *Cursor) Encode() string {
+	values := map[string]any{}
+	for _, k := range strings.Split(c.sort, ",") {
+		field := strings.TrimPrefix(k, "-")
+		values[field] = fieldValue(&c.key, field)
+	}
+
+	v, _ := json.Marshal(values)
+	b, _ := json.Marshal(cursorPayload{Sort: c.sort, Values: v})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(b, &p); err != nil || p.Sort == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{sort: p.Sort}
+	if err := json.Unmarshal(p.Values, &c.key); err != nil || c.key.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// Matches сообщает, построен ли курсор для указанной сортировки
+func (c *Cursor) Matches(sort []SortKey) bool {
+	return c.sort == FormatSort(orderKeys(sort))
+}
+
+// match сообщает, подходит ли задача под фильтр. done определяет, относится ли статус к категории done
+func (f *TaskFilter) match(t *models.Task, done func(status string) bool) bool {
+	if (t.DeletedAt != nil) != f.Deleted {
+		return false
+	}
+
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, t.Status) {
+		return false
+	}
+
+	if f.Open && done(t.Status) {
+		return false
+	}
+
+	if len(f.Priorities) > 0 && !slices.Contains(f.Priorities, t.Priority) {
+		return false
+	}
+
+	if f.ProjectID != nil && (t.ProjectID == nil || *t.ProjectID != *f.ProjectID) {
+		return false
+	}
+
+	if f.ParentID != nil && (t.ParentID == nil || *t.ParentID != *f.ParentID) {
+		return false
+	}
+
+	if f.SeriesID != nil && t.ID != *f.SeriesID && (t.SeriesID == nil || *t.SeriesID != *f.SeriesID) {
+		return false
+	}
+
+	if len(f.Tags) > 0 {
+		matched := 0
+		for _, tag := range f.Tags {
+			if slices.Contains(t.Tags, tag) {
+				matched++
+			}
+		}
+
+		if matched == 0 || (f.AllTags && matched < len(f.Tags)) {
+			return false
+		}
+	}
+
+	if f.CreatedAfter != nil && t.CreatedAt.Before(*f.CreatedAfter) {
+		return false
+	}
+	if f.CreatedBefore != nil && !t.CreatedAt.Before(*f.CreatedBefore) {
+		return false
+	}
+	if f.UpdatedAfter != nil && t.UpdatedAt.Before(*f.UpdatedAfter) {
+		return false
+	}
+	if f.UpdatedBefore != nil && !t.UpdatedAt.Before(*f.UpdatedBefore) {
+		return false
+	}
+	if f.DueAfter != nil && (t.DueAt == nil || t.DueAt.Before(*f.DueAfter)) {
+		return false
+	}
+	if f.DueBefore != nil && (t.DueAt == nil || !t.DueAt.Before(*f.DueBefore)) {
+		return false
+	}
+
+	if f.Text != "" {
+		text := strings.ToLower(f.Text)
+		if !strings.Contains(strings.ToLower(t.Title), text) && !strings.Contains(strings.ToLower(t.Description), text) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// after сообщает, находится ли задача строго после курсора в порядке keys
+func (c *Cursor) after(t *models.Task, keys []SortKey) bool {
+	return compareTasks(t, &c.key, keys) > 0
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	due := time.Date(2025, time.August, 20, 18, 0, 0, 0, time.UTC)
+	task := models.Task{
+		ID:        7,
+		Title:     "b",
+		Priority:  "high",
+		DueAt:     &due,
+		CreatedAt: time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}
+	keys := orderKeys([]SortKey{{Field: "priority", Desc: true}, {Field: "due_at"}})
+
+	c, err := DecodeCursor(newCursor(&task, keys).Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	if !c.Matches([]SortKey{{Field: "priority", Desc: true}, {Field: "due_at"}}) {
+		t.Errorf("cursor does not match the sort it was issued for")
+	}
+
+	for _, k := range keys {
+		if compareTasks(&c.key, &task, []SortKey{k}) != 0 {
+			t.Errorf("decoded key %s = %v, want %v", k.Field, fieldValue(&c.key, k.Field), fieldValue(&task, k.Field))
+		}
+	}
+}
+
+func TestCursorMatches(t *testing.T) {
+	task := models.Task{ID: 1}
+
+	tests := []struct {
+		name   string
+		issued []SortKey
+		sort   []SortKey
+		want   bool
+	}{
+		{"default sort", nil, nil, true},
+		{"explicit default sort", nil, []SortKey{{Field: "created_at"}}, true},
+		{"same sort", []SortKey{{Field: "title"}}, []SortKey{{Field: "title"}}, true},
+		{"different field", []SortKey{{Field: "title"}}, []SortKey{{Field: "status"}}, false},
+		{"different direction", []SortKey{{Field: "title"}}, []SortKey{{Field: "title", Desc: true}}, false},
+		{"different key order", []SortKey{{Field: "title"}, {Field: "status"}}, []SortKey{{Field: "status"}, {Field: "title"}}, false},
+		{"sort instead of default", nil, []SortKey{{Field: "updated_at", Desc: true}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := DecodeCursor(newCursor(&task, orderKeys(tt.issued)).Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+
+			if got := c.Matches(tt.sort); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", FormatSort(tt.sort), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	valid := newCursor(&models.Task{ID: 3, Title: "a"}, orderKeys(nil)).Encode()
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"truncated", valid[:len(valid)/2]},
+		{"not json", encode("cursor")},
+		{"no sort", encode(`{"v":{"id":3}}`)},
+		{"no values", encode(`{"s":"created_at,id"}`)},
+		{"no id", encode(`{"s":"created_at,id","v":{"created_at":"2025-08-01T00:00:00Z"}}`)},
+		{"wrong value type", encode(`{"s":"created_at,id","v":{"id":"3"}}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestListKeysetPagination(t *testing.T) {
+	ctx := context.Background()
+	due := func(day int) *time.Time {
+		d := time.Date(2025, time.August, day, 12, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	repo := NewMemoryTaskRepository()
+	for _, task := range []models.Task{
+		{Title: "a", Priority: "high", DueAt: due(3)},
+		{Title: "b", Priority: "low"},
+		{Title: "c", Priority: "high", DueAt: due(1)},
+		{Title: "d", Priority: "high"},
+		{Title: "e", Priority: "low", DueAt: due(3)},
+		{Title: "f", Priority: "high", DueAt: due(3)},
+	} {
+		if err := repo.Create(ctx, &task); err != nil {
+			t.Fatalf("Create(%s) error = %v", task.Title, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		sort []SortKey
+		want []string
+	}{
+		// равные приоритеты упорядочены по id
+		{"ties on sort key", []SortKey{{Field: "priority"}}, []string{"b", "e", "a", "c", "d", "f"}},
+		{"ties on descending sort key", []SortKey{{Field: "priority", Desc: true}}, []string{"a", "c", "d", "f", "b", "e"}},
+		// задачи без срока идут после задач со сроком, а при обратном порядке - перед ними
+		{"nullable due_at", []SortKey{{Field: "due_at"}}, []string{"c", "a", "e", "f", "b", "d"}},
+		{"descending nullable due_at", []SortKey{{Field: "due_at", Desc: true}}, []string{"b", "d", "a", "e", "f", "c"}},
+		{"descending due_at then title", []SortKey{{Field: "due_at", Desc: true}, {Field: "title", Desc: true}}, []string{"d", "b", "f", "e", "a", "c"}},
+	}
+
+	for _, tt := range tests {
+		for _, limit := range []int{1, 2, 4} {
+			t.Run(tt.name, func(t *testing.T) {
+				var got []string
+
+				params := ListParams{Sort: tt.sort, Limit: limit}
+				for page := 0; ; page++ {
+					if page > len(tt.want) {
+						t.Fatalf("pagination does not terminate, got %v", got)
+					}
+
+					res, err := repo.List(ctx, params)
+					if err != nil {
+						t.Fatalf("List() error = %v", err)
+					}
+					if res.Total != len(tt.want) {
+						t.Errorf("List() total = %d, want %d", res.Total, len(tt.want))
+					}
+
+					for _, task := range res.Tasks {
+						got = append(got, task.Title)
+					}
+
+					if res.Next == nil {
+						break
+					}
+
+					// курсор передается клиенту и возвращается им в следующем запросе
+					params.After, err = DecodeCursor(res.Next.Encode())
+					if err != nil {
+						t.Fatalf("DecodeCursor() error = %v", err)
+					}
+					if !params.After.Matches(tt.sort) {
+						t.Fatalf("next cursor does not match sort %q", FormatSort(tt.sort))
+					}
+				}
+
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("limit %d: got %v, want %v", limit, got, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,681 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// MemoryTaskRepository хранит задачи в памяти процесса.
+// Используется для разработки и CI, когда PostgreSQL недоступен
+type MemoryTaskRepository struct {
+	mu            sync.RWMutex
+	nextID        int
+	tasks         map[int]models.Task
+	nextProjectID int
+	projects      map[int]models.Project
+	nextTagID     int
+	tags          map[int]models.Tag
+	dependencies  map[dependency]bool
+	statuses      map[string]models.Status
+	nextHistoryID int
+	history       []models.StatusChange
+	nextAuditID   int
+	audit         []models.AuditEntry
+}
+
+func NewMemoryTaskRepository() *MemoryTaskRepository {
+	return &MemoryTaskRepository{
+		nextID:        1,
+		tasks:         map[int]models.Task{},
+		nextProjectID: 1,
+		projects:      map[int]models.Project{},
+		nextTagID:     1,
+		tags:          map[int]models.Tag{},
+		dependencies:  map[dependency]bool{},
+		statuses:      defaultStatuses(),
+		nextHistoryID: 1,
+		history:       []models.StatusChange{},
+		nextAuditID:   1,
+		audit:         []models.AuditEntry{},
+	}
+}
+
+func (r *MemoryTaskRepository) List(ctx context.Context, params ListParams) (*ListResult, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: list tasks", "limit", params.Limit, "offset", params.Offset)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make([]models.Task, 0, len(r.tasks))
+	for _, t := range r.tasks {
+		if params.Filter.match(&t, r.isDone) {
+			all = append(all, t)
+		}
+	}
+
+	keys := orderKeys(params.Sort)
+	sort.Slice(all, func(i, j int) bool {
+		return compareTasks(&all[i], &all[j], keys) < 0
+	})
+
+	total := len(all)
+
+	tasks := []models.Task{}
+	for i := range all {
+		if params.After != nil && !params.After.after(&all[i], keys) {
+			continue
+		}
+		tasks = append(tasks, all[i])
+	}
+
+	if params.Limit > 0 {
+		tasks = tasks[min(params.Offset, len(tasks)):]
+		tasks = tasks[:min(params.Limit+1, len(tasks))]
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: list tasks", "count", len(tasks), "total", total)
+	}
+
+	return paginate(tasks, params, total), nil
+}
+
+func (r *MemoryTaskRepository) Get(ctx context.Context, id int) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: get task", "id", id)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.activeTask(id)
+	if !ok {
+		slog.Warn("task not found", "task_id", id)
+		return nil, ErrNotFound
+	}
+
+	return &t, nil
+}
+
+func (r *MemoryTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return memoryTaskWriter{r}.create(ctx, task)
+}
+
+func (r *MemoryTaskRepository) Update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return memoryTaskWriter{r}.update(ctx, id, updates, version, opts)
+}
+
+func (r *MemoryTaskRepository) Modify(ctx context.Context, id int, modify func(t *models.Task) error, version int, opts UpdateOptions) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: modify task", "id", id, "version", version)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.activeTask(id)
+	if !ok {
+		slog.Warn("task not found for modification", "task_id", id)
+		return nil, ErrNotFound
+	}
+
+	if version > 0 && t.Version != version {
+		slog.Warn("task version mismatch for modification", "task_id", id, "version", version, "current", t.Version)
+		return nil, ErrVersionMismatch
+	}
+
+	before := t
+
+	if err := modify(&t); err != nil {
+		return nil, err
+	}
+
+	if t.ProjectID != nil {
+		if err := r.checkProject(*t.ProjectID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.ParentID != nil {
+		if err := r.checkParent(*t.ParentID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	change, err := r.statusChange(r.tasks[id].Status, t.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if change.done && !opts.IgnoreBlockers {
+		if err := r.checkOpenBlockers(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+
+	// completed подзадачи, выполненные вместе с задачей
+	completed := []int{}
+	if change.done {
+		completed = r.completeSubtasks(ctx, id, t.Status, now)
+	}
+
+	if change.from != change.to {
+		r.recordStatus(ctx, &t, change.from, now)
+	}
+
+	t.UpdatedAt = now
+	t.Version++
+	r.tasks[id] = t
+	r.recordAudit(ctx, &before, &t, now)
+
+	if relatedChanged(&before, &t) {
+		r.touchRelated(append([]int{id}, completed...), now, previousParent(&before, &t)...)
+	}
+
+	if change.completing() {
+		r.spawnOccurrence(ctx, &t, now)
+	}
+
+	r.countSubtasks()
+	t = r.tasks[id]
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: modify task", "id", id, "version", t.Version)
+	}
+
+	return &t, nil
+}
+
+func (r *MemoryTaskRepository) Delete(ctx context.Context, id int, version int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return memoryTaskWriter{r}.delete(ctx, id, version)
+}
+
+func (r *MemoryTaskRepository) Restore(ctx context.Context, id int) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: restore task", "id", id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		slog.Warn("task not found in trash", "task_id", id)
+		return nil, ErrNotFound
+	}
+
+	if t.ParentID != nil && r.tasks[*t.ParentID].DeletedAt != nil {
+		slog.Warn("parent task is deleted", "task_id", id)
+		return nil, ErrParentDeleted
+	}
+
+	// вместе с задачей восстанавливаются потомки, удаленные одновременно с ней
+	deletedAt := *t.DeletedAt
+	deletedTogether := func(t models.Task) bool {
+		return t.DeletedAt != nil && t.DeletedAt.Equal(deletedAt)
+	}
+
+	now := time.Now().UTC()
+	ids := r.subtree([]int{id}, deletedTogether)
+	for _, taskID := range ids {
+		t := r.tasks[taskID]
+		t.DeletedAt = nil
+		t.UpdatedAt = now
+		t.Version++
+		r.tasks[taskID] = t
+	}
+	r.touchRelated(ids, now)
+
+	r.recordAuditTasks(ctx, models.RestoreAuditAction, nil, r.taskList(ids), now)
+
+	r.countSubtasks()
+	t = r.tasks[id]
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: restore task", "id", id)
+	}
+
+	return &t, nil
+}
+
+func (r *MemoryTaskRepository) Purge(ctx context.Context, id int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: purge task", "id", id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		slog.Warn("task not found in trash", "task_id", id)
+		return ErrNotFound
+	}
+
+	// как и ON DELETE CASCADE в базах данных, вместе с задачей удаляются все ее потомки
+	ids := r.subtree([]int{id}, func(models.Task) bool { return true })
+	r.recordAuditTasks(ctx, models.PurgeAuditAction, r.taskList(ids), nil, time.Now().UTC())
+
+	for _, taskID := range ids {
+		delete(r.tasks, taskID)
+	}
+
+	r.dropDependencies()
+	r.dropHistory()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: purge task", "id", id)
+	}
+
+	return nil
+}
+
+func (r *MemoryTaskRepository) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: purge deleted tasks", "retention", retention)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	threshold := time.Now().UTC().Add(-retention)
+
+	expired := []int{}
+	for id, t := range r.tasks {
+		if t.DeletedAt != nil && t.DeletedAt.Before(threshold) {
+			expired = append(expired, id)
+		}
+	}
+
+	ids := r.subtree(expired, func(models.Task) bool { return true })
+	r.recordAuditTasks(ctx, models.PurgeAuditAction, r.taskList(ids), nil, time.Now().UTC())
+
+	n := 0
+	for _, id := range ids {
+		delete(r.tasks, id)
+		n++
+	}
+
+	r.dropDependencies()
+	r.dropHistory()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: purge deleted tasks", "rows_affected", n)
+	}
+
+	return n, nil
+}
+
+func (r *MemoryTaskRepository) Batch(ctx context.Context, ops []BatchOp, atomic bool) ([]BatchResult, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: batch", "operations", len(ops), "atomic", atomic)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// снимок состояния для отката атомарного пакета: задачи, история статусов и журнал аудита, см. taskWriter
+	tasks, nextID := maps.Clone(r.tasks), r.nextID
+	history, nextHistoryID := len(r.history), r.nextHistoryID
+	audit, nextAuditID := len(r.audit), r.nextAuditID
+
+	results := make([]BatchResult, len(ops))
+
+	for i, op := range ops {
+		results[i] = applyBatchOp(ctx, memoryTaskWriter{r}, op)
+		if atomic && results[i].Err != nil {
+			slog.Warn("batch aborted", "index", i, "error", results[i].Err)
+			r.tasks, r.nextID = tasks, nextID
+			r.history, r.nextHistoryID = r.history[:history], nextHistoryID
+			r.audit, r.nextAuditID = r.audit[:audit], nextAuditID
+			abortBatch(results, i)
+			return results, nil
+		}
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: batch", "operations", len(ops))
+	}
+
+	return results, nil
+}
+
+// memoryTaskWriter выполняет изменения задач, ожидая, что вызывающий уже захватил r.mu
+type memoryTaskWriter struct {
+	r *MemoryTaskRepository
+}
+
+func (w memoryTaskWriter) create(ctx context.Context, task *models.Task) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: create task", "title", task.Title, "status", task.Status)
+	}
+
+	if task.Status == "" {
+		task.Status = models.DefaultTaskStatus
+	}
+	if task.Priority == "" {
+		task.Priority = models.DefaultTaskPriority
+	}
+
+	if task.ProjectID != nil {
+		if err := w.r.checkProject(*task.ProjectID, 0); err != nil {
+			return err
+		}
+	}
+
+	if task.ParentID != nil {
+		if err := w.r.checkParent(*task.ParentID, 0); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.r.statusChange("", task.Status); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	task.ID = w.r.nextID
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	task.Version = 1
+	task.Tags = []string{}
+	w.r.recordStatus(ctx, task, "", now)
+	w.r.recordAudit(ctx, nil, task, now)
+
+	w.r.tasks[task.ID] = *task
+	w.r.nextID++
+
+	if task.ParentID != nil {
+		w.r.touchRelated([]int{task.ID}, now)
+	}
+
+	w.r.countSubtasks()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: create task", "id", task.ID)
+	}
+
+	return nil
+}
+
+func (w memoryTaskWriter) update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: update task", "id", id, "updates", updates, "version", version)
+	}
+
+	if len(updates) == 0 {
+		slog.Warn("no fields to update", "task_id", id)
+		return nil, fmt.Errorf("%w: no fields to update", ErrInvalid)
+	}
+
+	t, ok := w.r.activeTask(id)
+	if !ok {
+		slog.Warn("task not found for update", "task_id", id)
+		return nil, ErrNotFound
+	}
+
+	if version > 0 && t.Version != version {
+		slog.Warn("task version mismatch for update", "task_id", id, "version", version, "current", t.Version)
+		return nil, ErrVersionMismatch
+	}
+
+	if projectID, ok := updatedProjectID(updates); ok {
+		if err := w.r.checkProject(projectID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if parentID, ok := updatedParentID(updates); ok {
+		if err := w.r.checkParent(parentID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	before := t
+
+	for k, v := range updates {
+		if err := applyUpdate(&t, k, v); err != nil {
+			slog.Error("memory query failed: update task", "error", err, "task_id", id)
+			return nil, err
+		}
+	}
+
+	change, err := w.r.statusChange(w.r.tasks[id].Status, t.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if change.done && !opts.IgnoreBlockers {
+		if err := w.r.checkOpenBlockers(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+
+	// completed подзадачи, выполненные вместе с задачей
+	completed := []int{}
+	if change.done {
+		completed = w.r.completeSubtasks(ctx, id, t.Status, now)
+	}
+
+	if change.from != change.to {
+		w.r.recordStatus(ctx, &t, change.from, now)
+	}
+
+	t.UpdatedAt = now
+	t.Version++
+	w.r.tasks[id] = t
+	w.r.recordAudit(ctx, &before, &t, now)
+
+	if relatedChanged(&before, &t) {
+		w.r.touchRelated(append([]int{id}, completed...), now, previousParent(&before, &t)...)
+	}
+
+	if change.completing() {
+		w.r.spawnOccurrence(ctx, &t, now)
+	}
+
+	w.r.countSubtasks()
+	t = w.r.tasks[id]
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: update task", "id", id)
+	}
+
+	return &t, nil
+}
+
+func (w memoryTaskWriter) delete(ctx context.Context, id int, version int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: delete task", "id", id, "version", version)
+	}
+
+	t, ok := w.r.activeTask(id)
+	if !ok {
+		slog.Warn("no rows affected when deleting task", "task_id", id)
+		return ErrNotFound
+	}
+
+	if version > 0 && t.Version != version {
+		slog.Warn("task version mismatch for delete", "task_id", id, "version", version, "current", t.Version)
+		return ErrVersionMismatch
+	}
+
+	// задача перемещается в корзину вместе со всеми активными потомками
+	now := time.Now().UTC()
+	ids := w.r.subtree([]int{id}, isActiveTask)
+	for _, taskID := range ids {
+		t := w.r.tasks[taskID]
+		t.DeletedAt = &now
+		t.UpdatedAt = now
+		t.Version++
+		w.r.tasks[taskID] = t
+	}
+	w.r.touchRelated(ids, now)
+
+	w.r.recordAuditTasks(ctx, models.DeleteAuditAction, w.r.taskList(ids), nil, now)
+
+	w.r.countSubtasks()
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: delete task", "id", id)
+	}
+
+	return nil
+}
+
+// taskList возвращает копии задач ids. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) taskList(ids []int) []models.Task {
+	tasks := make([]models.Task, 0, len(ids))
+	for _, id := range ids {
+		if t, ok := r.tasks[id]; ok {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return tasks
+}
+
+// touchRelated увеличивает версию задач, в представление которых входят задачи ids, см. pgTouchRelated.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) touchRelated(ids []int, now time.Time, others ...int) {
+	changed := map[int]bool{}
+	related := map[int]bool{}
+	for _, id := range ids {
+		changed[id] = true
+		if parentID := r.tasks[id].ParentID; parentID != nil {
+			related[*parentID] = true
+		}
+	}
+	for d := range r.dependencies {
+		if changed[d.task] {
+			related[d.blocker] = true
+		}
+		if changed[d.blocker] {
+			related[d.task] = true
+		}
+	}
+	for _, id := range others {
+		related[id] = true
+	}
+
+	for id := range related {
+		t, ok := r.activeTask(id)
+		if !ok || changed[id] {
+			continue
+		}
+
+		t.UpdatedAt = now
+		t.Version++
+		r.tasks[id] = t
+	}
+}
+
+// activeTask возвращает задачу, если она существует и не находится в корзине
+func (r *MemoryTaskRepository) activeTask(id int) (models.Task, bool) {
+	t, ok := r.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return models.Task{}, false
+	}
+
+	return t, true
+}
+
+func applyUpdate(t *models.Task, field string, value any) error {
+	switch field {
+	case "title":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.Title = s
+	case "description":
+		if value == nil {
+			t.Description = ""
+			return nil
+		}
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.Description = s
+	case "status":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.Status = s
+	case "project_id":
+		id, ok := value.(*int)
+		if !ok && value != nil {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.ProjectID = nil
+		if id != nil {
+			projectID := *id
+			t.ProjectID = &projectID
+		}
+	case "parent_id":
+		id, ok := value.(*int)
+		if !ok && value != nil {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.ParentID = nil
+		if id != nil {
+			parentID := *id
+			t.ParentID = &parentID
+		}
+	case "priority":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.Priority = s
+	case "due_at":
+		due, ok := value.(*time.Time)
+		if !ok && value != nil {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.DueAt = nil
+		if due != nil {
+			dueAt := *due
+			t.DueAt = &dueAt
+		}
+	case "recurrence":
+		rule, ok := value.(*string)
+		if !ok && value != nil {
+			return fmt.Errorf("%w: invalid value for %s", ErrInvalid, field)
+		}
+		t.Recurrence = nil
+		if rule != nil {
+			recurrence := *rule
+			t.Recurrence = &recurrence
+		}
+	default:
+		return fmt.Errorf("%w: unknown field %s", ErrInvalid, field)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// mustCreate создает в r задачу с заголовком title и возвращает ее
+func mustCreate(t *testing.T, r *MemoryTaskRepository, title string) *models.Task {
+	t.Helper()
+
+	task := &models.Task{Title: title}
+	if err := r.Create(context.Background(), task); err != nil {
+		t.Fatalf("Create(%q) error = %v", title, err)
+	}
+
+	return task
+}
+
+// mustGet возвращает задачу id из r
+func mustGet(t *testing.T, r *MemoryTaskRepository, id int) *models.Task {
+	t.Helper()
+
+	task, err := r.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get(%d) error = %v", id, err)
+	}
+
+	return task
+}
+
+// mustAudit возвращает все записи журнала аудита r от новых к старым
+func mustAudit(t *testing.T, r *MemoryTaskRepository) []models.AuditEntry {
+	t.Helper()
+
+	entries, err := r.Audit(context.Background(), AuditFilter{})
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	return entries
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrProjectNotFound возвращается, когда задачу помещают в несуществующий проект
+	ErrProjectNotFound = fmt.Errorf("%w: project not found", ErrInvalid)
+	// ErrProjectArchived возвращается, когда задачу создают в архивном проекте или перемещают в него
+	ErrProjectArchived = fmt.Errorf("%w: project is archived", ErrConflict)
+	// ErrProjectNotEmpty возвращается при удалении проекта с активными задачами в режиме ProjectDeleteRestrict
+	ErrProjectNotEmpty = fmt.Errorf("%w: project has active tasks", ErrConflict)
+)
+
+// ProjectDeleteMode определяет, что происходит с задачами удаляемого проекта
+type ProjectDeleteMode string
+
+const (
+	// ProjectDeleteRestrict запрещает удаление проекта, в котором есть активные задачи
+	ProjectDeleteRestrict ProjectDeleteMode = "restrict"
+	// ProjectDeleteDetach оставляет задачи вне проектов
+	ProjectDeleteDetach ProjectDeleteMode = "detach"
+	// ProjectDeleteTrash перемещает активные задачи проекта в корзину
+	ProjectDeleteTrash ProjectDeleteMode = "trash"
+)
+
+// ProjectStore описывает хранилище проектов.
+// Задачу нельзя создать в архивном проекте или переместить в него: TaskStore возвращает ErrProjectArchived,
+// а для несуществующего проекта - ErrProjectNotFound. После удаления проекта все его задачи, включая
+// задачи из корзины, остаются без проекта
+type ProjectStore interface {
+	// ListProjects возвращает активные проекты или, при archived, архивные, упорядоченные по названию
+	ListProjects(ctx context.Context, archived bool) ([]models.Project, error)
+	GetProject(ctx context.Context, id int) (*models.Project, error)
+	CreateProject(ctx context.Context, project *models.Project) error
+	UpdateProject(ctx context.Context, id int, name, description string) (*models.Project, error)
+	// SetProjectArchived архивирует проект или возвращает его из архива
+	SetProjectArchived(ctx context.Context, id int, archived bool) (*models.Project, error)
+	DeleteProject(ctx context.Context, id int, mode ProjectDeleteMode) error
+}
+
+// projectColumns колонки проекта в порядке, ожидаемом scanProject
+const projectColumns = `id, name, COALESCE(description, ''), archived_at, created_at, updated_at`
+
+func scanProject(row rowScanner, p *models.Project) error {
+	return row.Scan(&p.ID, &p.Name, &p.Description, &p.ArchivedAt, &p.CreatedAt, &p.UpdatedAt)
+}
+
+// updatedProjectID возвращает проект из изменений задачи, если он передан и не пуст.
+// Значение project_id в updates - *int или nil
+func updatedProjectID(updates map[string]any) (int, bool) {
+	if id, ok := updates["project_id"].(*int); ok && id != nil {
+		return *id, true
+	}
+
+	return 0, false
+}
+
+func (r *TaskRepository) ListProjects(ctx context.Context, archived bool) ([]models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: list projects", "archived", archived)
+	}
+
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE archived_at IS NULL ORDER BY name, id`
+	if archived {
+		query = `SELECT ` + projectColumns + ` FROM projects WHERE archived_at IS NOT NULL ORDER BY name, id`
+	}
+
+	rows, err := r.dbPool.Query(ctx, query)
+	if err != nil {
+		slog.Error("database query failed: list projects", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+
+	for rows.Next() {
+		var p models.Project
+		if err := scanProject(rows, &p); err != nil {
+			slog.Error("failed to scan project row", "error", err)
+			return nil, err
+		}
+
+		projects = append(projects, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: list projects", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: list projects", "count", len(projects))
+	}
+
+	return projects, nil
+}
+
+func (r *TaskRepository) GetProject(ctx context.Context, id int) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: get project", "id", id)
+	}
+
+	p := &models.Project{}
+	if err := scanProject(r.dbPool.QueryRow(ctx, `SELECT `+projectColumns+` FROM projects WHERE id = $1`, id), p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("project not found", "project_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: get project", "error", err, "project_id", id)
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (r *TaskRepository) CreateProject(ctx context.Context, project *models.Project) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: create project", "name", project.Name)
+	}
+
+	query := `
+		INSERT INTO projects (name, description)
+		VALUES ($1, NULLIF($2, ''))
+		RETURNING ` + projectColumns
+
+	if err := scanProject(r.dbPool.QueryRow(ctx, query, project.Name, project.Description), project); err != nil {
+		slog.Error("database query failed: create project", "error", err, "name", project.Name)
+		return pgError(err)
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: create project", "id", project.ID)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) UpdateProject(ctx context.Context, id int, name, description string) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: update project", "id", id, "name", name)
+	}
+
+	query := `
+		UPDATE projects
+		SET name = $1, description = NULLIF($2, ''), updated_at = now()
+		WHERE id = $3
+		RETURNING ` + projectColumns
+
+	p := &models.Project{}
+	if err := scanProject(r.dbPool.QueryRow(ctx, query, name, description, id), p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("project not found for update", "project_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: update project", "error", err, "project_id", id)
+
+		return nil, pgError(err)
+	}
+
+	return p, nil
+}
+
+func (r *TaskRepository) SetProjectArchived(ctx context.Context, id int, archived bool) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: set project archived", "id", id, "archived", archived)
+	}
+
+	set := `archived_at = NULL`
+	if archived {
+		set = `archived_at = COALESCE(archived_at, now())`
+	}
+
+	query := `UPDATE projects SET ` + set + `, updated_at = now() WHERE id = $1 RETURNING ` + projectColumns
+
+	p := &models.Project{}
+	if err := scanProject(r.dbPool.QueryRow(ctx, query, id), p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("project not found for archiving", "project_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: set project archived", "error", err, "project_id", id)
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (r *TaskRepository) DeleteProject(ctx context.Context, id int, mode ProjectDeleteMode) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: delete project", "id", id, "mode", mode)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: delete project", "error", err, "project_id", id)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// блокировка проекта не дает одновременно добавлять в него задачи
+	var exists int
+	if err := tx.QueryRow(ctx, `SELECT 1 FROM projects WHERE id = $1 FOR UPDATE`, id).Scan(&exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("project not found for deletion", "project_id", id)
+			return ErrNotFound
+		}
+
+		slog.Error("database query failed: delete project", "error", err, "project_id", id)
+
+		return err
+	}
+
+	switch mode {
+	case ProjectDeleteRestrict:
+		var hasTasks bool
+		query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE project_id = $1 AND deleted_at IS NULL)`
+		if err := tx.QueryRow(ctx, query, id).Scan(&hasTasks); err != nil {
+			slog.Error("database query failed: delete project", "error", err, "project_id", id)
+			return err
+		}
+
+		if hasTasks {
+			slog.Warn("project has active tasks", "project_id", id)
+			return ErrProjectNotEmpty
+		}
+	case ProjectDeleteTrash:
+		// вместе с задачами проекта в корзину перемещаются все их активные потомки
+		query := subtreeCTE("project_id = $1 AND deleted_at IS NULL", "child.deleted_at IS NULL") + `
+			UPDATE tasks SET deleted_at = now(), updated_at = now(), version = version + 1
+			WHERE id IN (SELECT id FROM subtree)
+			RETURNING id`
+
+		ids, err := pgQueryIDs(ctx, tx, query, id)
+		if err != nil {
+			slog.Error("database query failed: trash project tasks", "error", err, "project_id", id)
+			return err
+		}
+
+		if err := pgTouchRelated(ctx, tx, ids); err != nil {
+			return err
+		}
+
+		trashed, err := pgGetTasks(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+
+		if err := pgAuditTasks(ctx, tx, models.DeleteAuditAction, trashed, nil); err != nil {
+			return err
+		}
+	case ProjectDeleteDetach:
+	default:
+		return fmt.Errorf("%w: unknown project delete mode %q", ErrInvalid, mode)
+	}
+
+	// задачи открепляются от проекта в любом режиме, в том числе задачи из корзины
+	before, err := pgQueryTasks(ctx, tx, `SELECT `+taskColumns+` FROM tasks WHERE project_id = $1 ORDER BY id`, id)
+	if err != nil {
+		slog.Error("database query failed: get project tasks", "error", err, "project_id", id)
+		return err
+	}
+
+	query := `UPDATE tasks SET project_id = NULL, updated_at = now(), version = version + 1 WHERE project_id = $1 RETURNING id`
+	ids, err := pgQueryIDs(ctx, tx, query, id)
+	if err != nil {
+		slog.Error("database query failed: detach project tasks", "error", err, "project_id", id)
+		return err
+	}
+
+	after, err := pgGetTasks(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	if err := pgAuditTasks(ctx, tx, models.UpdateAuditAction, before, after); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM projects WHERE id = $1`, id); err != nil {
+		slog.Error("database query failed: delete project", "error", err, "project_id", id)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: delete project", "error", err, "project_id", id)
+		return err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: delete project", "id", id)
+	}
+
+	return nil
+}
+
+// pgCheckProject проверяет, что задачу taskID можно поместить в проект: проект существует и, если задача
+// еще не в нем, не находится в архиве. Для новой задачи taskID равен 0
+func pgCheckProject(ctx context.Context, q pgQuerier, projectID, taskID int) error {
+	query := `
+		SELECT archived_at IS NOT NULL AND id IS DISTINCT FROM (SELECT project_id FROM tasks WHERE id = $2)
+		FROM projects
+		WHERE id = $1
+		FOR SHARE`
+
+	var blocked bool
+	if err := q.QueryRow(ctx, query, projectID, taskID).Scan(&blocked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("project not found", "project_id", projectID, "task_id", taskID)
+			return ErrProjectNotFound
+		}
+
+		slog.Error("database query failed: check project", "error", err, "project_id", projectID)
+
+		return err
+	}
+
+	if blocked {
+		slog.Warn("project is archived", "project_id", projectID, "task_id", taskID)
+		return ErrProjectArchived
+	}
+
+	return nil
+}
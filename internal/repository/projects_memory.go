@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+func (r *MemoryTaskRepository) ListProjects(ctx context.Context, archived bool) ([]models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: list projects", "archived", archived)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := []models.Project{}
+	for _, p := range r.projects {
+		if (p.ArchivedAt != nil) == archived {
+			projects = append(projects, p)
+		}
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+
+		return projects[i].ID < projects[j].ID
+	})
+
+	return projects, nil
+}
+
+func (r *MemoryTaskRepository) GetProject(ctx context.Context, id int) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: get project", "id", id)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.projects[id]
+	if !ok {
+		slog.Warn("project not found", "project_id", id)
+		return nil, ErrNotFound
+	}
+
+	return &p, nil
+}
+
+func (r *MemoryTaskRepository) CreateProject(ctx context.Context, project *models.Project) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: create project", "name", project.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkProjectName(0, project.Name); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	project.ID = r.nextProjectID
+	project.ArchivedAt = nil
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	r.projects[project.ID] = *project
+	r.nextProjectID++
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: create project", "id", project.ID)
+	}
+
+	return nil
+}
+
+func (r *MemoryTaskRepository) UpdateProject(ctx context.Context, id int, name, description string) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: update project", "id", id, "name", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.projects[id]
+	if !ok {
+		slog.Warn("project not found for update", "project_id", id)
+		return nil, ErrNotFound
+	}
+
+	if err := r.checkProjectName(id, name); err != nil {
+		return nil, err
+	}
+
+	p.Name = name
+	p.Description = description
+	p.UpdatedAt = time.Now().UTC()
+	r.projects[id] = p
+
+	return &p, nil
+}
+
+func (r *MemoryTaskRepository) SetProjectArchived(ctx context.Context, id int, archived bool) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: set project archived", "id", id, "archived", archived)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.projects[id]
+	if !ok {
+		slog.Warn("project not found for archiving", "project_id", id)
+		return nil, ErrNotFound
+	}
+
+	now := time.Now().UTC()
+
+	switch {
+	case !archived:
+		p.ArchivedAt = nil
+	case p.ArchivedAt == nil:
+		p.ArchivedAt = &now
+	}
+	p.UpdatedAt = now
+	r.projects[id] = p
+
+	return &p, nil
+}
+
+func (r *MemoryTaskRepository) DeleteProject(ctx context.Context, id int, mode ProjectDeleteMode) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: delete project", "id", id, "mode", mode)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[id]; !ok {
+		slog.Warn("project not found for deletion", "project_id", id)
+		return ErrNotFound
+	}
+
+	inProject := func(t models.Task) bool {
+		return t.ProjectID != nil && *t.ProjectID == id
+	}
+
+	switch mode {
+	case ProjectDeleteRestrict:
+		for _, t := range r.tasks {
+			if inProject(t) && t.DeletedAt == nil {
+				slog.Warn("project has active tasks", "project_id", id)
+				return ErrProjectNotEmpty
+			}
+		}
+	case ProjectDeleteTrash, ProjectDeleteDetach:
+	default:
+		return fmt.Errorf("%w: unknown project delete mode %q", ErrInvalid, mode)
+	}
+
+	now := time.Now().UTC()
+
+	if mode == ProjectDeleteTrash {
+		roots := []int{}
+		for taskID, t := range r.tasks {
+			if inProject(t) && t.DeletedAt == nil {
+				roots = append(roots, taskID)
+			}
+		}
+
+		// вместе с задачами проекта в корзину перемещаются все их активные потомки
+		ids := r.subtree(roots, isActiveTask)
+		for _, taskID := range ids {
+			t := r.tasks[taskID]
+			t.DeletedAt = &now
+			t.UpdatedAt = now
+			t.Version++
+			r.tasks[taskID] = t
+		}
+		r.touchRelated(ids, now)
+
+		r.recordAuditTasks(ctx, models.DeleteAuditAction, r.taskList(ids), nil, now)
+
+		r.countSubtasks()
+	}
+
+	var before, after []models.Task
+	for taskID, t := range r.tasks {
+		if !inProject(t) {
+			continue
+		}
+
+		before = append(before, t)
+		t.ProjectID = nil
+		t.UpdatedAt = now
+		t.Version++
+		r.tasks[taskID] = t
+		after = append(after, t)
+	}
+
+	r.recordAuditTasks(ctx, models.UpdateAuditAction, before, after, now)
+
+	delete(r.projects, id)
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: delete project", "id", id)
+	}
+
+	return nil
+}
+
+// checkProject проверяет, что задачу taskID можно поместить в проект, см. pgCheckProject.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) checkProject(projectID, taskID int) error {
+	p, ok := r.projects[projectID]
+	if !ok {
+		slog.Warn("project not found", "project_id", projectID, "task_id", taskID)
+		return ErrProjectNotFound
+	}
+
+	if p.ArchivedAt == nil {
+		return nil
+	}
+
+	if t, ok := r.tasks[taskID]; ok && t.ProjectID != nil && *t.ProjectID == projectID {
+		return nil
+	}
+
+	slog.Warn("project is archived", "project_id", projectID, "task_id", taskID)
+
+	return ErrProjectArchived
+}
+
+// checkProjectName эмулирует уникальность названия проекта. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) checkProjectName(id int, name string) error {
+	for _, p := range r.projects {
+		if p.ID != id && p.Name == name {
+			return fmt.Errorf("%w: project %q already exists", ErrConflict, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+func (r *SQLiteTaskRepository) ListProjects(ctx context.Context, archived bool) ([]models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: list projects", "archived", archived)
+	}
+
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE archived_at IS NULL ORDER BY name, id`
+	if archived {
+		query = `SELECT ` + projectColumns + ` FROM projects WHERE archived_at IS NOT NULL ORDER BY name, id`
+	}
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		slog.Error("sqlite query failed: list projects", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+
+	for rows.Next() {
+		var p models.Project
+		if err := scanProject(rows, &p); err != nil {
+			slog.Error("failed to scan project row", "error", err)
+			return nil, err
+		}
+
+		projects = append(projects, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: list projects", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: list projects", "count", len(projects))
+	}
+
+	return projects, nil
+}
+
+func (r *SQLiteTaskRepository) GetProject(ctx context.Context, id int) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: get project", "id", id)
+	}
+
+	p, err := sqliteGetProject(ctx, r.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("project not found", "project_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: get project", "error", err, "project_id", id)
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (r *SQLiteTaskRepository) CreateProject(ctx context.Context, project *models.Project) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: create project", "name", project.Name)
+	}
+
+	now := time.Now().UTC()
+
+	query := `
+		INSERT INTO projects (name, description, created_at, updated_at)
+		VALUES (?, NULLIF(?, ''), ?, ?)
+	`
+
+	res, err := r.db.ExecContext(ctx, query, project.Name, project.Description, now, now)
+	if err != nil {
+		slog.Error("sqlite query failed: create project", "error", err, "name", project.Name)
+		return sqliteError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		slog.Error("failed to get id of created project", "error", err, "name", project.Name)
+		return err
+	}
+
+	project.ID = int(id)
+	project.ArchivedAt = nil
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: create project", "id", project.ID)
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) UpdateProject(ctx context.Context, id int, name, description string) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: update project", "id", id, "name", name)
+	}
+
+	query := `UPDATE projects SET name = ?, description = NULLIF(?, ''), updated_at = ? WHERE id = ?`
+
+	return r.updateProject(ctx, id, query, name, description, time.Now().UTC(), id)
+}
+
+func (r *SQLiteTaskRepository) SetProjectArchived(ctx context.Context, id int, archived bool) (*models.Project, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: set project archived", "id", id, "archived", archived)
+	}
+
+	now := time.Now().UTC()
+
+	if archived {
+		query := `UPDATE projects SET archived_at = COALESCE(archived_at, ?), updated_at = ? WHERE id = ?`
+		return r.updateProject(ctx, id, query, now, now, id)
+	}
+
+	return r.updateProject(ctx, id, `UPDATE projects SET archived_at = NULL, updated_at = ? WHERE id = ?`, now, id)
+}
+
+// updateProject выполняет изменяющий проект запрос и возвращает проект после изменения
+func (r *SQLiteTaskRepository) updateProject(ctx context.Context, id int, query string, args ...any) (*models.Project, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: update project", "error", err, "project_id", id)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: update project", "error", err, "project_id", id)
+		return nil, sqliteError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: update project", "error", err, "project_id", id)
+		return nil, err
+	}
+
+	if n == 0 {
+		slog.Warn("project not found for update", "project_id", id)
+		return nil, ErrNotFound
+	}
+
+	p, err := sqliteGetProject(ctx, tx, id)
+	if err != nil {
+		slog.Error("sqlite query failed: update project", "error", err, "project_id", id)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: update project", "error", err, "project_id", id)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (r *SQLiteTaskRepository) DeleteProject(ctx context.Context, id int, mode ProjectDeleteMode) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: delete project", "id", id, "mode", mode)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: delete project", "error", err, "project_id", id)
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := sqliteGetProject(ctx, tx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("project not found for deletion", "project_id", id)
+			return ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: delete project", "error", err, "project_id", id)
+
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	switch mode {
+	case ProjectDeleteRestrict:
+		var hasTasks bool
+		query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE project_id = ? AND deleted_at IS NULL)`
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&hasTasks); err != nil {
+			slog.Error("sqlite query failed: delete project", "error", err, "project_id", id)
+			return err
+		}
+
+		if hasTasks {
+			slog.Warn("project has active tasks", "project_id", id)
+			return ErrProjectNotEmpty
+		}
+	case ProjectDeleteTrash:
+		// вместе с задачами проекта в корзину перемещаются все их активные потомки
+		query := subtreeCTE("project_id = ? AND deleted_at IS NULL", "child.deleted_at IS NULL") + `
+			UPDATE tasks SET deleted_at = ?, updated_at = ?, version = version + 1
+			WHERE id IN (SELECT id FROM subtree)
+			RETURNING id`
+
+		ids, err := sqliteQueryIDs(ctx, tx, query, id, now, now)
+		if err != nil {
+			slog.Error("sqlite query failed: trash project tasks", "error", err, "project_id", id)
+			return err
+		}
+
+		if err := sqliteTouchRelated(ctx, tx, ids); err != nil {
+			return err
+		}
+
+		trashed, err := sqliteGetTasks(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+
+		if err := sqliteAuditTasks(ctx, tx, models.DeleteAuditAction, trashed, nil, now); err != nil {
+			return err
+		}
+	case ProjectDeleteDetach:
+	default:
+		return fmt.Errorf("%w: unknown project delete mode %q", ErrInvalid, mode)
+	}
+
+	// задачи открепляются от проекта в любом режиме, в том числе задачи из корзины
+	before, err := sqliteQueryTasks(ctx, tx, `SELECT `+taskColumns+` FROM tasks WHERE project_id = ? ORDER BY id`, id)
+	if err != nil {
+		slog.Error("sqlite query failed: get project tasks", "error", err, "project_id", id)
+		return err
+	}
+
+	query := `UPDATE tasks SET project_id = NULL, updated_at = ?, version = version + 1 WHERE project_id = ? RETURNING id`
+	ids, err := sqliteQueryIDs(ctx, tx, query, now, id)
+	if err != nil {
+		slog.Error("sqlite query failed: detach project tasks", "error", err, "project_id", id)
+		return err
+	}
+
+	after, err := sqliteGetTasks(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	if err := sqliteAuditTasks(ctx, tx, models.UpdateAuditAction, before, after, now); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM projects WHERE id = ?`, id); err != nil {
+		slog.Error("sqlite query failed: delete project", "error", err, "project_id", id)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: delete project", "error", err, "project_id", id)
+		return err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: delete project", "id", id)
+	}
+
+	return nil
+}
+
+func sqliteGetProject(ctx context.Context, q sqliteQuerier, id int) (*models.Project, error) {
+	p := &models.Project{}
+	if err := scanProject(q.QueryRowContext(ctx, `SELECT `+projectColumns+` FROM projects WHERE id = ?`, id), p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// sqliteCheckProject проверяет, что задачу taskID можно поместить в проект, см. pgCheckProject
+func sqliteCheckProject(ctx context.Context, q sqliteQuerier, projectID, taskID int) error {
+	query := `
+		SELECT archived_at IS NOT NULL AND id IS NOT (SELECT project_id FROM tasks WHERE id = ?)
+		FROM projects
+		WHERE id = ?`
+
+	var blocked bool
+	if err := q.QueryRowContext(ctx, query, taskID, projectID).Scan(&blocked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("project not found", "project_id", projectID, "task_id", taskID)
+			return ErrProjectNotFound
+		}
+
+		slog.Error("sqlite query failed: check project", "error", err, "project_id", projectID)
+
+		return err
+	}
+
+	if blocked {
+		slog.Warn("project is archived", "project_id", projectID, "task_id", taskID)
+		return ErrProjectArchived
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// taskColumns колонки задачи в порядке, ожидаемом scanTask. Метки задачи выбираются одной строкой
+// через запятую: названия меток не содержат запятых. string_agg поддерживают и PostgreSQL, и SQLite.
+// Прогресс подзадач считается по активным подзадачам первого уровня
+const taskColumns = `id, title, COALESCE(description, ''), status, project_id, parent_id, priority, due_at,
+	recurrence, series_id, spawned_from, created_at, updated_at, started_at, completed_at, version, deleted_at,
+	COALESCE((
+		SELECT string_agg(tags.name, ',' ORDER BY tags.name)
+		FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+		WHERE task_tags.task_id = tasks.id
+	), ''),
+	(SELECT count(*) FROM tasks sub WHERE sub.parent_id = tasks.id AND sub.deleted_at IS NULL
+		AND sub.status IN ` + doneStatuses + `),
+	(SELECT count(*) FROM tasks sub WHERE sub.parent_id = tasks.id AND sub.deleted_at IS NULL)`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner, t *models.Task, extra ...any) error {
+	var tags string
+
+	dest := []any{
+		&t.ID, &t.Title, &t.Description, &t.Status, &t.ProjectID, &t.ParentID, &t.Priority, &t.DueAt,
+		&t.Recurrence, &t.SeriesID, &t.SpawnedFrom, &t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt, &t.Version, &t.DeletedAt,
+		&tags, &t.Subtasks.Done, &t.Subtasks.Total,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	t.Tags = splitTags(tags)
+
+	return nil
+}
+
+// splitTags разбирает метки, выбранные taskColumns
+func splitTags(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(s, ",")
+}
+
+// sqlDialect различия в синтаксисе запросов между PostgreSQL и SQLite
+type sqlDialect struct {
+	placeholder func(n int) string
+	ilike       string
+}
+
+var (
+	pgDialect = sqlDialect{
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		ilike:       "ILIKE",
+	}
+	// LIKE в SQLite регистронезависим только для ASCII
+	sqliteDialect = sqlDialect{
+		placeholder: func(int) string { return "?" },
+		ilike:       "LIKE",
+	}
+)
+
+// sqlBuilder накапливает условия WHERE и аргументы запроса,
+// подставляя плейсхолдеры в синтаксисе конкретной СУБД
+type sqlBuilder struct {
+	dialect sqlDialect
+	where   []string
+	args    []any
+}
+
+func (b *sqlBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return b.dialect.placeholder(len(b.args))
+}
+
+func (b *sqlBuilder) filter(f TaskFilter) {
+	if f.Deleted {
+		b.where = append(b.where, "deleted_at IS NOT NULL")
+	} else {
+		b.where = append(b.where, "deleted_at IS NULL")
+	}
+
+	if len(f.Statuses) > 0 {
+		placeholders := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			placeholders[i] = b.arg(s)
+		}
+		b.where = append(b.where, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if f.Open {
+		b.where = append(b.where, "status NOT IN "+doneStatuses)
+	}
+
+	if len(f.Priorities) > 0 {
+		placeholders := make([]string, len(f.Priorities))
+		for i, p := range f.Priorities {
+			placeholders[i] = b.arg(p)
+		}
+		b.where = append(b.where, "priority IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if f.ProjectID != nil {
+		b.where = append(b.where, "project_id = "+b.arg(*f.ProjectID))
+	}
+
+	if f.ParentID != nil {
+		b.where = append(b.where, "parent_id = "+b.arg(*f.ParentID))
+	}
+
+	if f.SeriesID != nil {
+		b.where = append(b.where, "(id = "+b.arg(*f.SeriesID)+" OR series_id = "+b.arg(*f.SeriesID)+")")
+	}
+
+	if len(f.Tags) > 0 {
+		placeholders := make([]string, len(f.Tags))
+		for i, tag := range f.Tags {
+			placeholders[i] = b.arg(tag)
+		}
+
+		tagged := "SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name IN (" +
+			strings.Join(placeholders, ", ") + ")"
+		if f.AllTags {
+			tagged += " GROUP BY task_tags.task_id HAVING count(*) = " + b.arg(len(f.Tags))
+		}
+
+		b.where = append(b.where, "id IN ("+tagged+")")
+	}
+
+	if f.CreatedAfter != nil {
+		b.where = append(b.where, "created_at >= "+b.arg(f.CreatedAfter.UTC()))
+	}
+	if f.CreatedBefore != nil {
+		b.where = append(b.where, "created_at < "+b.arg(f.CreatedBefore.UTC()))
+	}
+	if f.UpdatedAfter != nil {
+		b.where = append(b.where, "updated_at >= "+b.arg(f.UpdatedAfter.UTC()))
+	}
+	if f.UpdatedBefore != nil {
+		b.where = append(b.where, "updated_at < "+b.arg(f.UpdatedBefore.UTC()))
+	}
+	if f.DueAfter != nil {
+		b.where = append(b.where, "due_at >= "+b.arg(f.DueAfter.UTC()))
+	}
+	if f.DueBefore != nil {
+		b.where = append(b.where, "due_at < "+b.arg(f.DueBefore.UTC()))
+	}
+
+	if f.Text != "" {
+		pattern := "%" + likeEscaper.Replace(f.Text) + "%"
+		b.where = append(b.where, fmt.Sprintf(
+			`(title %[1]s %[2]s ESCAPE '\' OR description %[1]s %[3]s ESCAPE '\')`,
+			b.dialect.ilike, b.arg(pattern), b.arg(pattern),
+		))
+	}
+}
+
+// after добавляет keyset условие "строго после курсора" для порядка keys:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., где для убывающих ключей используется "<"
+func (b *sqlBuilder) after(c *Cursor, keys []SortKey) {
+	ors := make([]string, len(keys))
+
+	for i, k := range keys {
+		ands := make([]string, 0, i+1)
+		for _, prev := range keys[:i] {
+			ands = append(ands, b.sortExpr(prev.Field)+" = "+b.arg(sortValue(&c.key, prev.Field)))
+		}
+
+		op := " > "
+		if k.Desc {
+			op = " < "
+		}
+		ands = append(ands, b.sortExpr(k.Field)+op+b.arg(sortValue(&c.key, k.Field)))
+
+		ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+	}
+
+	b.where = append(b.where, "("+strings.Join(ors, " OR ")+")")
+}
+
+func (b *sqlBuilder) whereSQL() string {
+	if len(b.where) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(b.where, " AND ")
+}
+
+// buildListQueries возвращает запрос страницы задач и запрос общего количества задач.
+// Запрос страницы выбирает на одну строку больше лимита, чтобы определить наличие следующей страницы
+func buildListQueries(p ListParams, d sqlDialect) (query string, args []any, countQuery string, countArgs []any) {
+	keys := orderKeys(p.Sort)
+
+	b := &sqlBuilder{dialect: d}
+	b.filter(p.Filter)
+
+	countQuery = "SELECT count(*) FROM tasks" + b.whereSQL()
+	countArgs = append([]any{}, b.args...)
+
+	if p.After != nil {
+		b.after(p.After, keys)
+	}
+
+	query = "SELECT " + taskColumns + " FROM tasks" + b.whereSQL() + b.orderBy(keys)
+
+	if p.Limit > 0 {
+		query += " LIMIT " + b.arg(p.Limit+1)
+
+		if p.Offset > 0 {
+			query += " OFFSET " + b.arg(p.Offset)
+		}
+	}
+
+	return query, b.args, countQuery, countArgs
+}
+
+// paginate обрезает лишнюю строку, выбранную buildListQueries, и заполняет курсор следующей страницы
+func paginate(tasks []models.Task, p ListParams, total int) *ListResult {
+	res := &ListResult{Tasks: tasks, Total: total}
+
+	if p.Limit > 0 && len(tasks) > p.Limit {
+		res.Tasks = tasks[:p.Limit]
+		res.Next = newCursor(&res.Tasks[p.Limit-1], orderKeys(p.Sort))
+	}
+
+	return res
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/recurrence"
+)
+
+// nextOccurrence возвращает следующее повторение задачи t, выполненной в момент now, или nil, если у задачи нет
+// правила повторения или серия закончилась. Срок следующего повторения отсчитывается от срока задачи, а у задачи
+// без срока - от момента выполнения. Повторение наследует поля и метки задачи, но не ее подзадачи и связи блокировки
+func nextOccurrence(t *models.Task, now time.Time) *models.Task {
+	if t.Recurrence == nil {
+		return nil
+	}
+
+	rule, err := recurrence.Parse(*t.Recurrence)
+	if err != nil {
+		// правила проверяются при сохранении задачи
+		slog.Error("invalid stored recurrence rule", "error", err, "task_id", t.ID, "recurrence", *t.Recurrence)
+		return nil
+	}
+
+	following, ok := rule.Advance()
+	if !ok {
+		return nil
+	}
+
+	base := now.Truncate(time.Second)
+	if t.DueAt != nil {
+		base = *t.DueAt
+	}
+
+	dueAt, ok := rule.Next(base.UTC())
+	if !ok {
+		return nil
+	}
+
+	seriesID := t.ID
+	if t.SeriesID != nil {
+		seriesID = *t.SeriesID
+	}
+
+	recur := following.String()
+
+	return &models.Task{
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      models.DefaultTaskStatus,
+		ProjectID:   t.ProjectID,
+		ParentID:    t.ParentID,
+		Priority:    t.Priority,
+		DueAt:       &dueAt,
+		Recurrence:  &recur,
+		SeriesID:    &seriesID,
+		SpawnedFrom: &t.ID,
+		Tags:        slices.Clone(t.Tags),
+	}
+}
+
+// pgSpawnOccurrence создает следующее повторение только что выполненной задачи t вместе с ее метками.
+// Повторение не создается, если задача уже создала его раньше, например когда задачу вернули в работу
+// и выполнили снова
+func pgSpawnOccurrence(ctx context.Context, q pgQuerier, t *models.Task) error {
+	next := nextOccurrence(t, time.Now().UTC())
+	if next == nil {
+		return nil
+	}
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE spawned_from = $1)`
+	if err := q.QueryRow(ctx, query, t.ID).Scan(&exists); err != nil {
+		slog.Error("database query failed: check task occurrence", "error", err, "task_id", t.ID)
+		return err
+	}
+
+	if exists {
+		if slog.Default().Enabled(ctx, slog.LevelDebug) {
+			slog.Debug("task occurrence already exists", "task_id", t.ID, "series_id", *next.SeriesID)
+		}
+
+		return nil
+	}
+
+	if err := pgInsertTask(ctx, q, next); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO task_tags (task_id, tag_id) SELECT $1, tag_id FROM task_tags WHERE task_id = $2`
+	if _, err := q.Exec(ctx, query, next.ID, t.ID); err != nil {
+		slog.Error("database query failed: copy task tags", "error", err, "task_id", t.ID)
+		return pgError(err)
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: create task occurrence", "task_id", t.ID, "id", next.ID, "series_id", *next.SeriesID)
+	}
+
+	return nil
+}
+
+// pgSpawnOccurrences создает следующие повторения повторяющихся задач из tasks, выполненных вместе
+// с родительской задачей, см. pgSpawnOccurrence
+func pgSpawnOccurrences(ctx context.Context, q pgQuerier, tasks []models.Task) error {
+	for i := range tasks {
+		if err := pgSpawnOccurrence(ctx, q, &tasks[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// spawnOccurrence создает следующее повторение выполненной задачи t, см. pgSpawnOccurrence.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) spawnOccurrence(ctx context.Context, t *models.Task, now time.Time) {
+	next := nextOccurrence(t, now)
+	if next == nil {
+		return
+	}
+
+	for _, existing := range r.tasks {
+		if existing.SpawnedFrom != nil && *existing.SpawnedFrom == t.ID {
+			if slog.Default().Enabled(ctx, slog.LevelDebug) {
+				slog.Debug("task occurrence already exists", "task_id", t.ID, "series_id", *next.SeriesID)
+			}
+
+			return
+		}
+	}
+
+	next.ID = r.nextID
+	next.CreatedAt = now
+	next.UpdatedAt = now
+	next.Version = 1
+	r.recordStatus(ctx, next, "", now)
+	r.recordAudit(ctx, nil, next, now)
+
+	r.tasks[next.ID] = *next
+	r.nextID++
+
+	if next.ParentID != nil {
+		r.touchRelated([]int{next.ID}, now)
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: create task occurrence", "task_id", t.ID, "id", next.ID, "series_id", *next.SeriesID)
+	}
+}
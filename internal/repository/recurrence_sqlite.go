@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// sqliteSpawnOccurrence создает следующее повторение выполненной задачи t, см. pgSpawnOccurrence
+func sqliteSpawnOccurrence(ctx context.Context, q sqliteQuerier, t *models.Task) error {
+	next := nextOccurrence(t, time.Now().UTC())
+	if next == nil {
+		return nil
+	}
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE spawned_from = ?)`
+	if err := q.QueryRowContext(ctx, query, t.ID).Scan(&exists); err != nil {
+		slog.Error("sqlite query failed: check task occurrence", "error", err, "task_id", t.ID)
+		return err
+	}
+
+	if exists {
+		if slog.Default().Enabled(ctx, slog.LevelDebug) {
+			slog.Debug("task occurrence already exists", "task_id", t.ID, "series_id", *next.SeriesID)
+		}
+
+		return nil
+	}
+
+	if err := sqliteInsertTask(ctx, q, next); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO task_tags (task_id, tag_id) SELECT ?, tag_id FROM task_tags WHERE task_id = ?`
+	if _, err := q.ExecContext(ctx, query, next.ID, t.ID); err != nil {
+		slog.Error("sqlite query failed: copy task tags", "error", err, "task_id", t.ID)
+		return sqliteError(err)
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: create task occurrence", "task_id", t.ID, "id", next.ID, "series_id", *next.SeriesID)
+	}
+
+	return nil
+}
+
+// sqliteSpawnOccurrences создает следующие повторения повторяющихся задач из tasks, см. pgSpawnOccurrences
+func sqliteSpawnOccurrences(ctx context.Context, q sqliteQuerier, tasks []models.Task) error {
+	for i := range tasks {
+		if err := sqliteSpawnOccurrence(ctx, q, &tasks[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
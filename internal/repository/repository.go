@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// TaskStore описывает хранилище задач, не зависящее от HTTP слоя.
+// Update и Delete при version > 0 применяются, только если текущая версия задачи равна version,
+// иначе возвращают ErrVersionMismatch. При version == 0 изменения применяются безусловно.
+// Ошибки базы данных возвращаются как ErrNotFound, ErrConflict или ErrInvalid, если их причина известна.
+// Delete перемещает задачу в корзину: Get, Update, Modify и Delete не видят удаленные задачи,
+// а List возвращает их только с фильтром Deleted
+type TaskStore interface {
+	List(ctx context.Context, params ListParams) (*ListResult, error)
+	Get(ctx context.Context, id int) (*models.Task, error)
+	Create(ctx context.Context, task *models.Task) error
+	Update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error)
+	// Modify в одной транзакции читает задачу, передает ее в modify и сохраняет измененные
+	// title, description и status. Ошибка modify откатывает транзакцию и возвращается как есть
+	Modify(ctx context.Context, id int, modify func(t *models.Task) error, version int, opts UpdateOptions) (*models.Task, error)
+	Delete(ctx context.Context, id int, version int) error
+	// Restore возвращает задачу из корзины
+	Restore(ctx context.Context, id int) (*models.Task, error)
+	// Purge безвозвратно удаляет задачу из корзины
+	Purge(ctx context.Context, id int) error
+	// PurgeDeleted безвозвратно удаляет задачи, которые находятся в корзине дольше retention,
+	// и возвращает количество удаленных задач
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int, error)
+	// Batch выполняет операции в одной транзакции. В атомарном режиме первая ошибка отменяет пакет:
+	// неудавшаяся операция получает свою ошибку, остальные - ErrBatchAborted. Иначе каждая операция
+	// применяется независимо. Ошибка возвращается, только если не удалось выполнить саму транзакцию
+	Batch(ctx context.Context, ops []BatchOp, atomic bool) ([]BatchResult, error)
+}
+
+// UpdateOptions параметры Update и Modify
+type UpdateOptions struct {
+	// IgnoreBlockers переводит задачу в done без проверки блокирующих задач
+	IgnoreBlockers bool
+}
+
+// Store объединяет хранилища задач, проектов, меток, связей между задачами, процесса работы над задачами,
+// истории статусов и журнала аудита одной базы данных
+type Store interface {
+	TaskStore
+	ProjectStore
+	TagStore
+	SubtaskStore
+	DependencyStore
+	StatusStore
+	HistoryStore
+	AuditStore
+}
+
+var (
+	_ Store = (*TaskRepository)(nil)
+	_ Store = (*MemoryTaskRepository)(nil)
+	_ Store = (*SQLiteTaskRepository)(nil)
+)
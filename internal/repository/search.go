@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// searchConfig конфигурация текстового поиска PostgreSQL, совпадает с колонкой tasks.search
+const searchConfig = "russian"
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+
+var (
+	// searchTokenRe фраза в кавычках или слово
+	searchTokenRe = regexp.MustCompile(`"[^"]*"?|\S+`)
+	// prefixTermRe слово с завершающей звездочкой, например "прогр*"
+	prefixTermRe = regexp.MustCompile(`^([\p{L}\p{N}_]+)\*$`)
+)
+
+// TaskSearcher реализуется хранилищами, поддерживающими полнотекстовый поиск по задачам
+type TaskSearcher interface {
+	Search(ctx context.Context, params SearchParams) (*SearchResult, error)
+}
+
+var _ TaskSearcher = (*TaskRepository)(nil)
+
+// SearchParams параметры полнотекстового поиска.
+// Query поддерживает синтаксис websearch_to_tsquery ("фраза в кавычках", or, -исключение)
+// и префиксные слова вида "прогр*"
+type SearchParams struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+type SearchResult struct {
+	Tasks []models.TaskSearchResult
+	Total int
+}
+
+func (r *TaskRepository) Search(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: search tasks", "query", params.Query, "limit", params.Limit, "offset", params.Offset)
+	}
+
+	b := &sqlBuilder{dialect: pgDialect}
+	cte := "WITH q AS (SELECT " + b.tsquery(params.Query) + " AS query)"
+
+	var total int
+	countQuery := cte + " SELECT count(*) FROM tasks, q WHERE search @@ q.query AND deleted_at IS NULL"
+	if err := r.dbPool.QueryRow(ctx, countQuery, b.args...).Scan(&total); err != nil {
+		slog.Error("database query failed: count search results", "error", err)
+		return nil, err
+	}
+
+	query := cte + `
+		SELECT ` + taskColumns + `,
+			ts_rank_cd(search, q.query) AS rank,
+			ts_headline('` + searchConfig + `', title, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>'),
+			ts_headline('` + searchConfig + `', COALESCE(description, ''), q.query, '` + headlineOptions + `')
+		FROM tasks, q
+		WHERE search @@ q.query AND deleted_at IS NULL
+		ORDER BY rank DESC, id`
+	if params.Limit > 0 {
+		query += " LIMIT " + b.arg(params.Limit) + " OFFSET " + b.arg(params.Offset)
+	}
+
+	rows, err := r.dbPool.Query(ctx, query, b.args...)
+	if err != nil {
+		slog.Error("database query failed: search tasks", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &SearchResult{Tasks: []models.TaskSearchResult{}, Total: total}
+
+	for rows.Next() {
+		var t models.TaskSearchResult
+		if err := scanTask(rows, &t.Task, &t.Rank, &t.Highlight.Title, &t.Highlight.Description); err != nil {
+			slog.Error("failed to scan search result row", "error", err)
+			return nil, err
+		}
+
+		res.Tasks = append(res.Tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: search tasks", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: search tasks", "count", len(res.Tasks), "total", total)
+	}
+
+	return res, nil
+}
+
+// tsquery возвращает SQL выражение tsquery для пользовательского запроса: префиксные слова
+// собираются в to_tsquery с :*, остальной текст разбирается websearch_to_tsquery
+func (b *sqlBuilder) tsquery(q string) string {
+	var plainTokens, prefixes []string
+	for _, token := range searchTokenRe.FindAllString(q, -1) {
+		if m := prefixTermRe.FindStringSubmatch(token); m != nil {
+			prefixes = append(prefixes, m[1]+":*")
+			continue
+		}
+		plainTokens = append(plainTokens, token)
+	}
+	plain := strings.Join(plainTokens, " ")
+
+	parts := []string{}
+	if plain != "" {
+		parts = append(parts, "websearch_to_tsquery('"+searchConfig+"', "+b.arg(plain)+")")
+	}
+	if len(prefixes) > 0 {
+		parts = append(parts, "to_tsquery('"+searchConfig+"', "+b.arg(strings.Join(prefixes, " & "))+")")
+	}
+
+	if len(parts) == 0 {
+		return "''::tsquery"
+	}
+
+	return strings.Join(parts, " && ")
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// SortableFields поля задачи, по которым допускается сортировка списка
+var SortableFields = []string{"id", "title", "status", "priority", "due_at", "created_at", "updated_at"}
+
+// noDueAt заменяет отсутствующий срок при сортировке, чтобы задачи без срока шли после задач со сроком
+var noDueAt = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+// SortKey ключ сортировки списка задач
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// FormatSort возвращает каноническую запись сортировки в формате query параметра sort
+func FormatSort(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k.Field
+		if k.Desc {
+			parts[i] = "-" + k.Field
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// orderKeys дополняет сортировку до полного порядка: по умолчанию задачи упорядочены по created_at,
+// а при равенстве всех ключей - по id
+func orderKeys(sort []SortKey) []SortKey {
+	if len(sort) == 0 {
+		sort = []SortKey{{Field: "created_at"}}
+	}
+
+	for _, k := range sort {
+		if k.Field == "id" {
+			return sort
+		}
+	}
+
+	return append(sort[:len(sort):len(sort)], SortKey{Field: "id"})
+}
+
+func (b *sqlBuilder) orderBy(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		dir := " ASC"
+		if k.Desc {
+			dir = " DESC"
+		}
+		parts[i] = b.sortExpr(k.Field) + dir
+	}
+
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// sortExpr возвращает SQL выражение ключа сортировки: приоритеты сравниваются по важности,
+// а отсутствующий срок заменяется noDueAt. Значения для сравнения с ним возвращает sortValue
+func (b *sqlBuilder) sortExpr(field string) string {
+	switch field {
+	case "priority":
+		whens := make([]string, len(models.TaskPriorities))
+		for i, p := range models.TaskPriorities {
+			whens[i] = "WHEN '" + p + "' THEN " + strconv.Itoa(i)
+		}
+
+		return "CASE priority " + strings.Join(whens, " ") + " END"
+	case "due_at":
+		return "COALESCE(due_at, " + b.arg(noDueAt) + ")"
+	default:
+		return field
+	}
+}
+
+// sortValue возвращает значение ключа сортировки задачи для сравнения с sortExpr
+func sortValue(t *models.Task, field string) any {
+	switch field {
+	case "priority":
+		return priorityRank(t.Priority)
+	case "due_at":
+		return dueAtKey(t)
+	default:
+		return fieldValue(t, field)
+	}
+}
+
+func priorityRank(priority string) int {
+	return slices.Index(models.TaskPriorities, priority)
+}
+
+func dueAtKey(t *models.Task) time.Time {
+	if t.DueAt == nil {
+		return noDueAt
+	}
+
+	return t.DueAt.UTC()
+}
+
+// fieldValue возвращает значение поля задачи в том виде, в каком оно передается клиенту в курсоре
+func fieldValue(t *models.Task, field string) any {
+	switch field {
+	case "id":
+		return t.ID
+	case "title":
+		return t.Title
+	case "status":
+		return t.Status
+	case "priority":
+		return t.Priority
+	case "due_at":
+		return t.DueAt
+	case "created_at":
+		return t.CreatedAt
+	case "updated_at":
+		return t.UpdatedAt
+	default:
+		return nil
+	}
+}
+
+// compareTasks сравнивает задачи в порядке keys
+func compareTasks(a, b *models.Task, keys []SortKey) int {
+	for _, k := range keys {
+		var c int
+
+		switch k.Field {
+		case "id":
+			c = cmp.Compare(a.ID, b.ID)
+		case "title":
+			c = strings.Compare(a.Title, b.Title)
+		case "status":
+			c = strings.Compare(a.Status, b.Status)
+		case "priority":
+			c = cmp.Compare(priorityRank(a.Priority), priorityRank(b.Priority))
+		case "due_at":
+			c = dueAtKey(a).Compare(dueAtKey(b))
+		case "created_at":
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		case "updated_at":
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		}
+
+		if k.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,926 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// SQLiteTaskRepository хранит задачи во встроенной базе SQLite.
+// Используется для развертывания одним бинарником без PostgreSQL
+type SQLiteTaskRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteTaskRepository(db *sql.DB) *SQLiteTaskRepository {
+	return &SQLiteTaskRepository{db: db}
+}
+
+func (r *SQLiteTaskRepository) List(ctx context.Context, params ListParams) (*ListResult, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: list tasks", "limit", params.Limit, "offset", params.Offset)
+	}
+
+	query, args, countQuery, countArgs := buildListQueries(params, sqliteDialect)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		slog.Error("sqlite query failed: count tasks", "error", err)
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: list tasks", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			slog.Error("failed to scan task row", "error", err)
+
+			return nil, err
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: list tasks", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: list tasks", "count", len(tasks), "total", total)
+	}
+
+	return paginate(tasks, params, total), nil
+}
+
+func (r *SQLiteTaskRepository) Get(ctx context.Context, id int) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: get task", "id", id)
+	}
+
+	t, err := sqliteGetTask(ctx, r.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("task not found", "task_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: get task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: get task", "id", id)
+	}
+
+	return t, nil
+}
+
+func (r *SQLiteTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: create task", "error", err, "title", task.Title)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := sqliteCreateTask(ctx, tx, task); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: create task", "error", err, "task_id", task.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) Update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: update task", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t, err := sqliteUpdateTask(ctx, tx, id, updates, version, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: update task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *SQLiteTaskRepository) Modify(ctx context.Context, id int, modify func(t *models.Task) error, version int, opts UpdateOptions) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: modify task", "id", id, "version", version)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: modify task", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t, err := sqliteGetTask(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("task not found for modification", "task_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: modify task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	if version > 0 && t.Version != version {
+		slog.Warn("task version mismatch for modification", "task_id", id, "version", version, "current", t.Version)
+		return nil, ErrVersionMismatch
+	}
+
+	status, before := t.Status, *t
+
+	if err := modify(t); err != nil {
+		return nil, err
+	}
+
+	if t.ProjectID != nil {
+		if err := sqliteCheckProject(ctx, tx, *t.ProjectID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.ParentID != nil {
+		if err := sqliteCheckParent(ctx, tx, *t.ParentID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	change, err := sqliteStatusChange(ctx, tx, status, t.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// completed подзадачи, выполненные вместе с задачей
+	completed := []int{}
+	if change.done {
+		if !opts.IgnoreBlockers {
+			if err := sqliteCheckOpenBlockers(ctx, tx, id); err != nil {
+				return nil, err
+			}
+		}
+
+		if completed, err = sqliteCompleteSubtasks(ctx, tx, id, t.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		UPDATE tasks
+		SET title = ?1, description = NULLIF(?2, ''), status = ?3, project_id = ?4, parent_id = ?5, priority = ?6,
+			due_at = ?7, recurrence = ?8, updated_at = ?9, version = version + 1, ` + statusTimesSet("?3", "?9") + `
+		WHERE id = ?10`
+
+	now := time.Now().UTC()
+	args := []any{t.Title, t.Description, t.Status, t.ProjectID, t.ParentID, t.Priority, t.DueAt, t.Recurrence, now, id}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		slog.Error("sqlite query failed: modify task", "error", err, "task_id", id)
+		return nil, sqliteError(err)
+	}
+
+	if t, err = sqliteGetTask(ctx, tx, id); err != nil {
+		slog.Error("sqlite query failed: modify task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if status != t.Status {
+		if err := sqliteRecordStatus(ctx, tx, id, status, t.Status, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sqliteAudit(ctx, tx, id, &before, t, now); err != nil {
+		return nil, err
+	}
+
+	if relatedChanged(&before, t) {
+		if err := sqliteTouchRelated(ctx, tx, append([]int{id}, completed...), previousParent(&before, t)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if change.completing() {
+		if err := sqliteSpawnOccurrence(ctx, tx, t); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: modify task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: modify task", "id", id, "version", t.Version)
+	}
+
+	return t, nil
+}
+
+func (r *SQLiteTaskRepository) Delete(ctx context.Context, id int, version int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: delete task", "error", err, "task_id", id)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := sqliteDeleteTask(ctx, tx, id, version); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: delete task", "error", err, "task_id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) Restore(ctx context.Context, id int) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: restore task", "id", id)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: restore task", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT EXISTS (SELECT 1 FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NOT NULL)
+		FROM tasks
+		WHERE id = ? AND deleted_at IS NOT NULL`
+
+	var parentDeleted bool
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&parentDeleted); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("task not found in trash", "task_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: restore task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	if parentDeleted {
+		slog.Warn("parent task is deleted", "task_id", id)
+		return nil, ErrParentDeleted
+	}
+
+	// вместе с задачей восстанавливаются потомки, удаленные одновременно с ней
+	query = subtreeCTE("id = ?", "child.deleted_at = (SELECT deleted_at FROM tasks root WHERE root.id = ?)") + `
+		UPDATE tasks SET deleted_at = NULL, updated_at = ?, version = version + 1
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING id`
+
+	now := time.Now().UTC()
+
+	ids, err := sqliteQueryIDs(ctx, tx, query, id, id, now)
+	if err != nil {
+		slog.Error("sqlite query failed: restore task", "error", err, "task_id", id)
+		return nil, sqliteError(err)
+	}
+
+	if err := sqliteTouchRelated(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+
+	restored, err := sqliteGetTasks(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqliteAuditTasks(ctx, tx, models.RestoreAuditAction, nil, restored, now); err != nil {
+		return nil, err
+	}
+
+	t, err := sqliteGetTask(ctx, tx, id)
+	if err != nil {
+		slog.Error("sqlite query failed: restore task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: restore task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: restore task", "id", id)
+	}
+
+	return t, nil
+}
+
+func (r *SQLiteTaskRepository) Purge(ctx context.Context, id int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: purge task", "id", id)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: purge task", "error", err, "task_id", id)
+		return err
+	}
+	defer tx.Rollback()
+
+	n, err := sqlitePurgeTasks(ctx, tx, `id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		slog.Warn("task not found in trash", "task_id", id)
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: purge task", "error", err, "task_id", id)
+		return err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: purge task", "id", id)
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: purge deleted tasks", "retention", retention)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: purge deleted tasks", "error", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	n, err := sqlitePurgeTasks(ctx, tx, `deleted_at < ?`, time.Now().UTC().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: purge deleted tasks", "error", err)
+		return 0, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: purge deleted tasks", "rows_affected", n)
+	}
+
+	return n, nil
+}
+
+// sqlitePurgeTasks безвозвратно удаляет задачи, выбранные условием where, см. pgPurgeTasks
+func sqlitePurgeTasks(ctx context.Context, q sqliteQuerier, where string, args ...any) (int, error) {
+	purged, err := sqliteQueryTasks(ctx, q, subtreeCTE(where, "true")+`
+		SELECT `+taskColumns+` FROM tasks WHERE id IN (SELECT id FROM subtree) ORDER BY id`, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: get purged tasks", "error", err)
+		return 0, err
+	}
+
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	res, err := q.ExecContext(ctx, `DELETE FROM tasks WHERE `+where, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: purge tasks", "error", err)
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: purge tasks", "error", err)
+		return 0, err
+	}
+
+	if err := sqliteAuditTasks(ctx, q, models.PurgeAuditAction, purged, nil, time.Now().UTC()); err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+func (r *SQLiteTaskRepository) Batch(ctx context.Context, ops []BatchOp, atomic bool) ([]BatchResult, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: batch", "operations", len(ops), "atomic", atomic)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: batch", "error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]BatchResult, len(ops))
+
+	for i, op := range ops {
+		if atomic {
+			results[i] = applyBatchOp(ctx, sqliteTaskWriter{tx}, op)
+			if results[i].Err != nil {
+				slog.Warn("batch aborted", "index", i, "error", results[i].Err)
+				abortBatch(results, i)
+				return results, nil
+			}
+
+			continue
+		}
+
+		// в режиме best-effort каждая операция выполняется в своей точке сохранения,
+		// чтобы ее частичные изменения откатывались независимо от остальных
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_op`); err != nil {
+			slog.Error("failed to create savepoint: batch", "error", err, "index", i)
+			return nil, err
+		}
+
+		results[i] = applyBatchOp(ctx, sqliteTaskWriter{tx}, op)
+		if results[i].Err != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO batch_op`); err != nil {
+				slog.Error("failed to rollback savepoint: batch", "error", err, "index", i)
+				return nil, err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `RELEASE batch_op`); err != nil {
+			slog.Error("failed to release savepoint: batch", "error", err, "index", i)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: batch", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: batch", "operations", len(ops))
+	}
+
+	return results, nil
+}
+
+// sqliteTaskWriter выполняет операции пакета в транзакции SQLite
+type sqliteTaskWriter struct {
+	q sqliteQuerier
+}
+
+func (w sqliteTaskWriter) create(ctx context.Context, task *models.Task) error {
+	return sqliteCreateTask(ctx, w.q, task)
+}
+
+func (w sqliteTaskWriter) update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
+	return sqliteUpdateTask(ctx, w.q, id, updates, version, opts)
+}
+
+func (w sqliteTaskWriter) delete(ctx context.Context, id int, version int) error {
+	return sqliteDeleteTask(ctx, w.q, id, version)
+}
+
+func sqliteCreateTask(ctx context.Context, q sqliteQuerier, task *models.Task) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: create task", "title", task.Title, "status", task.Status)
+	}
+
+	if task.Status == "" {
+		task.Status = models.DefaultTaskStatus
+	}
+	if task.Priority == "" {
+		task.Priority = models.DefaultTaskPriority
+	}
+
+	if task.ProjectID != nil {
+		if err := sqliteCheckProject(ctx, q, *task.ProjectID, 0); err != nil {
+			return err
+		}
+	}
+
+	if task.ParentID != nil {
+		if err := sqliteCheckParent(ctx, q, *task.ParentID, 0); err != nil {
+			return err
+		}
+	}
+
+	if _, err := sqliteStatusChange(ctx, q, "", task.Status); err != nil {
+		return err
+	}
+
+	if err := sqliteInsertTask(ctx, q, task); err != nil {
+		return err
+	}
+
+	task.Tags = []string{}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: create task", "id", task.ID)
+	}
+
+	return nil
+}
+
+// sqliteInsertTask вставляет задачу без проверок, заполняет ID, даты и версию и записывает создание задачи
+// в историю статусов и журнал аудита
+func sqliteInsertTask(ctx context.Context, q sqliteQuerier, task *models.Task) error {
+	now := time.Now().UTC()
+
+	query := `
+		INSERT INTO tasks (
+			title, description, status, project_id, parent_id, priority, due_at, recurrence, series_id, spawned_from,
+			created_at, updated_at, started_at, completed_at
+		)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?11, ` + statusTimesValues("?3", "?11") + `)
+		RETURNING id, started_at, completed_at
+	`
+
+	row := q.QueryRowContext(
+		ctx, query, task.Title, task.Description, task.Status, task.ProjectID, task.ParentID, task.Priority, task.DueAt,
+		task.Recurrence, task.SeriesID, task.SpawnedFrom, now,
+	)
+	if err := row.Scan(&task.ID, &task.StartedAt, &task.CompletedAt); err != nil {
+		slog.Error("sqlite query failed: create task", "error", err, "title", task.Title)
+		return sqliteError(err)
+	}
+
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	task.Version = 1
+
+	if err := sqliteRecordStatus(ctx, q, task.ID, "", task.Status, now); err != nil {
+		return err
+	}
+
+	if task.ParentID != nil {
+		if err := sqliteTouchRelated(ctx, q, []int{task.ID}); err != nil {
+			return err
+		}
+	}
+
+	return sqliteAudit(ctx, q, task.ID, nil, task, now)
+}
+
+func sqliteUpdateTask(ctx context.Context, q sqliteQuerier, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: update task", "id", id, "updates", updates, "version", version)
+	}
+
+	if len(updates) == 0 {
+		slog.Warn("no fields to update", "task_id", id)
+		return nil, fmt.Errorf("%w: no fields to update", ErrInvalid)
+	}
+
+	if projectID, ok := updatedProjectID(updates); ok {
+		if err := sqliteCheckProject(ctx, q, projectID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if parentID, ok := updatedParentID(updates); ok {
+		if err := sqliteCheckParent(ctx, q, parentID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	// before задача до изменения для журнала аудита
+	before, err := sqliteGetTask(ctx, q, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Error("sqlite query failed: update task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	// current прежний статус задачи, если updates его меняют, completed - подзадачи, выполненные вместе с ней
+	current, completing, completed := "", false, []int{}
+	if status, ok := updates["status"].(string); ok {
+		if before != nil {
+			current = before.Status
+		}
+
+		change, err := sqliteStatusChange(ctx, q, current, status)
+		if err != nil {
+			return nil, err
+		}
+
+		if change.done {
+			if !opts.IgnoreBlockers {
+				if err := sqliteCheckOpenBlockers(ctx, q, id); err != nil {
+					return nil, err
+				}
+			}
+
+			if completed, err = sqliteCompleteSubtasks(ctx, q, id, status); err != nil {
+				return nil, err
+			}
+		}
+
+		completing = change.completing()
+	}
+
+	setClauses := []string{}
+	args := []any{}
+	for k, v := range updates {
+		if !updatableColumns[k] {
+			return nil, fmt.Errorf("%w: unknown field %s", ErrInvalid, k)
+		}
+		args = append(args, v)
+		setClauses = append(setClauses, fmt.Sprintf("%s = ?%d", k, len(args)))
+	}
+
+	now := time.Now().UTC()
+	args = append(args, now)
+	nowArg := fmt.Sprintf("?%d", len(args))
+	setClauses = append(setClauses, "updated_at = "+nowArg, "version = version + 1")
+
+	if status, ok := updates["status"].(string); ok {
+		args = append(args, status)
+		setClauses = append(setClauses, statusTimesSet(fmt.Sprintf("?%d", len(args)), nowArg))
+	}
+
+	args = append(args, id)
+	where := fmt.Sprintf("id = ?%d AND deleted_at IS NULL", len(args))
+	if version > 0 {
+		args = append(args, version)
+		where += fmt.Sprintf(" AND version = ?%d", len(args))
+	}
+
+	query := fmt.Sprintf(`UPDATE tasks SET %s WHERE %s`, strings.Join(setClauses, ", "), where)
+
+	res, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: update task", "error", err, "task_id", id)
+		return nil, sqliteError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: update task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if n == 0 {
+		slog.Warn("task not updated: not found or version mismatch", "task_id", id, "version", version)
+		return nil, sqliteMissingTaskError(ctx, q, id, version)
+	}
+
+	t, err := sqliteGetTask(ctx, q, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("task not found for update", "task_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: update task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	if current != "" && current != t.Status {
+		if err := sqliteRecordStatus(ctx, q, id, current, t.Status, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sqliteAudit(ctx, q, id, before, t, now); err != nil {
+		return nil, err
+	}
+
+	if relatedChanged(before, t) {
+		if err := sqliteTouchRelated(ctx, q, append([]int{id}, completed...), previousParent(before, t)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if completing {
+		if err := sqliteSpawnOccurrence(ctx, q, t); err != nil {
+			return nil, err
+		}
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: update task", "id", id)
+	}
+
+	return t, nil
+}
+
+func sqliteDeleteTask(ctx context.Context, q sqliteQuerier, id int, version int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: delete task", "id", id, "version", version)
+	}
+
+	// задача перемещается в корзину вместе со всеми активными потомками
+	root := `id = ? AND deleted_at IS NULL`
+	args := []any{id}
+	if version > 0 {
+		root += ` AND version = ?`
+		args = append(args, version)
+	}
+	now := time.Now().UTC()
+	args = append(args, now, now)
+
+	query := subtreeCTE(root, "child.deleted_at IS NULL") + `
+		UPDATE tasks SET deleted_at = ?, updated_at = ?, version = version + 1
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING id`
+
+	ids, err := sqliteQueryIDs(ctx, q, query, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: delete task", "error", err, "task_id", id)
+		return sqliteError(err)
+	}
+
+	if len(ids) == 0 {
+		slog.Warn("no rows affected when deleting task", "task_id", id, "version", version)
+		return sqliteMissingTaskError(ctx, q, id, version)
+	}
+
+	if err := sqliteTouchRelated(ctx, q, ids); err != nil {
+		return err
+	}
+
+	deleted, err := sqliteGetTasks(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+
+	if err := sqliteAuditTasks(ctx, q, models.DeleteAuditAction, deleted, nil, now); err != nil {
+		return err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: delete task", "id", id, "rows_affected", len(ids))
+	}
+
+	return nil
+}
+
+// sqliteQuerier общий интерфейс *sql.DB и *sql.Tx
+type sqliteQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqliteQueryTasks выполняет запрос, возвращающий колонки taskColumns, см. pgQueryTasks
+func sqliteQueryTasks(ctx context.Context, q sqliteQuerier, query string, args ...any) ([]models.Task, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
+
+// sqliteGetTasks возвращает задачи ids, в том числе из корзины, по возрастанию ID
+func sqliteGetTasks(ctx context.Context, q sqliteQuerier, ids []int) ([]models.Task, error) {
+	if len(ids) == 0 {
+		return []models.Task{}, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id IN (?` + strings.Repeat(", ?", len(ids)-1) + `) ORDER BY id`
+
+	tasks, err := sqliteQueryTasks(ctx, q, query, args...)
+	if err != nil {
+		slog.Error("sqlite query failed: get tasks", "error", err, "task_ids", ids)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// sqliteQueryIDs выполняет запрос, возвращающий ID задач
+func sqliteQueryIDs(ctx context.Context, q sqliteQuerier, query string, args ...any) ([]int, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// sqliteTouchRelated увеличивает версию задач, в представление которых входят задачи ids, см. pgTouchRelated
+func sqliteTouchRelated(ctx context.Context, q sqliteQuerier, ids []int, others ...int) error {
+	b := sqlBuilder{dialect: sqliteDialect}
+	in := func(ids []int) string {
+		placeholders := make([]string, len(ids))
+		for i, id := range ids {
+			placeholders[i] = b.arg(id)
+		}
+
+		return "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	query := `
+		UPDATE tasks SET updated_at = ` + b.arg(time.Now().UTC()) + `, version = version + 1
+		WHERE deleted_at IS NULL AND id NOT IN ` + in(ids) + ` AND (
+			id IN (SELECT parent_id FROM tasks WHERE id IN ` + in(ids) + `)
+			OR id IN (SELECT blocker_id FROM task_dependencies WHERE task_id IN ` + in(ids) + `)
+			OR id IN (SELECT task_id FROM task_dependencies WHERE blocker_id IN ` + in(ids) + `)
+			OR id IN ` + in(others) + `
+		)`
+
+	if _, err := q.ExecContext(ctx, query, b.args...); err != nil {
+		slog.Error("sqlite query failed: touch related tasks", "error", err, "task_ids", ids)
+		return err
+	}
+
+	return nil
+}
+
+func sqliteGetTask(ctx context.Context, q sqliteQuerier, id int) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = ? AND deleted_at IS NULL`
+
+	t := &models.Task{}
+	if err := scanTask(q.QueryRowContext(ctx, query, id), t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// sqliteMissingTaskError определяет, почему условное изменение задачи не затронуло ни одной строки:
+// задачи нет или ее версия отличается от ожидаемой
+func sqliteMissingTaskError(ctx context.Context, q sqliteQuerier, id int, version int) error {
+	if version == 0 {
+		return ErrNotFound
+	}
+
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		slog.Error("sqlite query failed: check task existence", "error", err, "task_id", id)
+		return err
+	}
+
+	if exists {
+		return ErrVersionMismatch
+	}
+
+	return ErrNotFound
+}
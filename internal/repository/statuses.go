@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrStatusNotFound возвращается, когда задаче или переходу назначают несуществующий статус
+	ErrStatusNotFound = fmt.Errorf("%w: status not found", ErrInvalid)
+	// ErrStatusTransition возвращается, когда процесс не допускает перевод задачи из ее статуса в новый
+	ErrStatusTransition = fmt.Errorf("%w: status transition not allowed", ErrConflict)
+	// ErrStatusInUse возвращается при удалении статуса, в котором есть задачи, в том числе в корзине
+	ErrStatusInUse = fmt.Errorf("%w: status is used by tasks", ErrConflict)
+	// ErrStatusBuiltin возвращается при удалении встроенного статуса или изменении его категории
+	ErrStatusBuiltin = fmt.Errorf("%w: built-in status", ErrConflict)
+)
+
+// StatusStore описывает процесс работы над задачами: статусы с порядком, категорией и допустимыми переходами.
+// Задача в статусе категории done считается выполненной. TaskStore принимает только существующие статусы,
+// иначе возвращает ErrStatusNotFound, и переводит задачу в другой статус, только если процесс допускает
+// этот переход, иначе возвращает ErrStatusTransition. Новая задача может получить любой статус.
+// Встроенные статусы models.DefaultTaskStatus и models.DoneTaskStatus нельзя удалить или перенести
+// в другую категорию: первый назначается новым задачам и повторениям задач, а второй гарантирует,
+// что в процессе есть статус выполненных задач
+type StatusStore interface {
+	// ListStatuses возвращает статусы, упорядоченные по позиции и названию. Переходы статуса упорядочены так же
+	ListStatuses(ctx context.Context) ([]models.Status, error)
+	// CreateStatus создает статус с переходами из него. При Position == 0 статус добавляется в конец процесса
+	CreateStatus(ctx context.Context, status *models.Status) error
+	// UpdateStatus заменяет категорию, позицию и переходы статуса status.Name. При Position == 0 позиция
+	// не изменяется. Изменение категории увеличивает версию задач, подзадачи которых находятся в этом статусе
+	UpdateStatus(ctx context.Context, status *models.Status) error
+	// DeleteStatus удаляет статус вместе с переходами в него и из него
+	DeleteStatus(ctx context.Context, name string) error
+}
+
+// isBuiltinStatus сообщает, что статус встроенный и не может быть удален
+func isBuiltinStatus(name string) bool {
+	return name == models.DefaultTaskStatus || name == models.DoneTaskStatus
+}
+
+// builtinCategory возвращает ErrStatusBuiltin, если category меняет категорию встроенного статуса name
+func builtinCategory(name, category string) error {
+	if (name == models.DefaultTaskStatus && category != models.TodoStatusCategory) ||
+		(name == models.DoneTaskStatus && category != models.DoneStatusCategory) {
+		slog.Warn("built-in status category change", "status", name, "category", category)
+		return ErrStatusBuiltin
+	}
+
+	return nil
+}
+
+// doneStatuses выбирает статусы выполненных задач
+const doneStatuses = `(SELECT name FROM statuses WHERE category = '` + models.DoneStatusCategory + `')`
+
+// statusChangeQuery проверяет статус %[2]s и переход в него из статуса %[1]s
+const statusChangeQuery = `
+	SELECT
+		COALESCE((SELECT category = '` + models.DoneStatusCategory + `' FROM statuses WHERE name = %[1]s), false),
+		category = '` + models.DoneStatusCategory + `',
+		EXISTS (SELECT 1 FROM status_transitions WHERE from_status = %[1]s AND to_status = %[2]s)
+	FROM statuses
+	WHERE name = %[2]s`
+
+// statusChange изменение статуса задачи из from в to. Пустой from означает новую задачу
+type statusChange struct {
+	from, to string
+	// wasDone и done сообщают, относятся ли статусы from и to к категории done
+	wasDone, done bool
+	// allowed сообщает, что процесс допускает переход из from в to
+	allowed bool
+}
+
+// check возвращает ErrStatusTransition, если задача меняет статус, а процесс не допускает этот переход
+func (c statusChange) check() error {
+	if c.from == "" || c.from == c.to || c.allowed {
+		return nil
+	}
+
+	slog.Warn("status transition not allowed", "from", c.from, "to", c.to)
+
+	return ErrStatusTransition
+}
+
+// completing сообщает, что изменение выполняет невыполненную задачу
+func (c statusChange) completing() bool {
+	return c.done && !c.wasDone
+}
+
+func (r *TaskRepository) ListStatuses(ctx context.Context) ([]models.Status, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: list statuses")
+	}
+
+	rows, err := r.dbPool.Query(ctx, `SELECT name, category, position FROM statuses ORDER BY position, name`)
+	if err != nil {
+		slog.Error("database query failed: list statuses", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := []models.Status{}
+	byName := map[string]int{}
+
+	for rows.Next() {
+		s := models.Status{Transitions: []string{}}
+		if err := rows.Scan(&s.Name, &s.Category, &s.Position); err != nil {
+			slog.Error("failed to scan status row", "error", err)
+			return nil, err
+		}
+
+		byName[s.Name] = len(statuses)
+		statuses = append(statuses, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: list statuses", "error", err)
+		return nil, err
+	}
+
+	rows, err = r.dbPool.Query(ctx, statusTransitionsQuery)
+	if err != nil {
+		slog.Error("database query failed: list status transitions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from, to string
+		if err := rows.Scan(&from, &to); err != nil {
+			slog.Error("failed to scan status transition row", "error", err)
+			return nil, err
+		}
+
+		if i, ok := byName[from]; ok {
+			statuses[i].Transitions = append(statuses[i].Transitions, to)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: list status transitions", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: list statuses", "count", len(statuses))
+	}
+
+	return statuses, nil
+}
+
+// statusTransitionsQuery выбирает переходы, упорядоченные по позиции и названию целевого статуса
+const statusTransitionsQuery = `
+	SELECT status_transitions.from_status, status_transitions.to_status
+	FROM status_transitions JOIN statuses ON statuses.name = status_transitions.to_status
+	ORDER BY statuses.position, statuses.name`
+
+func (r *TaskRepository) CreateStatus(ctx context.Context, status *models.Status) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: create status", "name", status.Name, "category", status.Category)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: create status", "error", err, "status", status.Name)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO statuses (name, category, position)
+		VALUES ($1, $2, COALESCE(NULLIF($3, 0), (SELECT COALESCE(max(position), 0) + 1 FROM statuses)))
+		RETURNING position`
+
+	if err := tx.QueryRow(ctx, query, status.Name, status.Category, status.Position).Scan(&status.Position); err != nil {
+		slog.Error("database query failed: create status", "error", err, "status", status.Name)
+		return pgError(err)
+	}
+
+	if err := pgSetTransitions(ctx, tx, status); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: create status", "error", err, "status", status.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) UpdateStatus(ctx context.Context, status *models.Status) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: update status", "name", status.Name, "category", status.Category)
+	}
+
+	if err := builtinCategory(status.Name, status.Category); err != nil {
+		return err
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: update status", "error", err, "status", status.Name)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var category string
+	if err := tx.QueryRow(ctx, `SELECT category FROM statuses WHERE name = $1 FOR UPDATE`, status.Name).Scan(&category); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("status not found for update", "status", status.Name)
+			return ErrNotFound
+		}
+
+		slog.Error("database query failed: update status", "error", err, "status", status.Name)
+
+		return err
+	}
+
+	query := `
+		UPDATE statuses SET category = $2, position = COALESCE(NULLIF($3, 0), position)
+		WHERE name = $1
+		RETURNING position`
+
+	if err := tx.QueryRow(ctx, query, status.Name, status.Category, status.Position).Scan(&status.Position); err != nil {
+		slog.Error("database query failed: update status", "error", err, "status", status.Name)
+		return pgError(err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM status_transitions WHERE from_status = $1`, status.Name); err != nil {
+		slog.Error("database query failed: update status", "error", err, "status", status.Name)
+		return err
+	}
+
+	if err := pgSetTransitions(ctx, tx, status); err != nil {
+		return err
+	}
+
+	if category != status.Category {
+		query = `
+			UPDATE tasks SET version = version + 1
+			WHERE id IN (SELECT parent_id FROM tasks WHERE status = $1 AND parent_id IS NOT NULL AND deleted_at IS NULL)`
+
+		if _, err := tx.Exec(ctx, query, status.Name); err != nil {
+			slog.Error("database query failed: touch parent tasks", "error", err, "status", status.Name)
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: update status", "error", err, "status", status.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) DeleteStatus(ctx context.Context, name string) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: delete status", "name", name)
+	}
+
+	if isBuiltinStatus(name) {
+		slog.Warn("built-in status deletion", "status", name)
+		return ErrStatusBuiltin
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: delete status", "error", err, "status", name)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists int
+	if err := tx.QueryRow(ctx, `SELECT 1 FROM statuses WHERE name = $1 FOR UPDATE`, name).Scan(&exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("status not found for deletion", "status", name)
+			return ErrNotFound
+		}
+
+		slog.Error("database query failed: delete status", "error", err, "status", name)
+
+		return err
+	}
+
+	var used bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE status = $1)`, name).Scan(&used); err != nil {
+		slog.Error("database query failed: delete status", "error", err, "status", name)
+		return err
+	}
+
+	if used {
+		slog.Warn("status is used by tasks", "status", name)
+		return ErrStatusInUse
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM statuses WHERE name = $1`, name); err != nil {
+		slog.Error("database query failed: delete status", "error", err, "status", name)
+		return pgError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: delete status", "error", err, "status", name)
+		return err
+	}
+
+	return nil
+}
+
+// pgSetTransitions добавляет переходы из статуса status. Целевые статусы должны существовать
+func pgSetTransitions(ctx context.Context, q pgQuerier, status *models.Status) error {
+	for _, to := range status.Transitions {
+		var exists int
+		if err := q.QueryRow(ctx, `SELECT 1 FROM statuses WHERE name = $1`, to).Scan(&exists); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				slog.Warn("transition status not found", "status", status.Name, "to", to)
+				return ErrStatusNotFound
+			}
+
+			slog.Error("database query failed: check transition status", "error", err, "to", to)
+
+			return err
+		}
+
+		query := `INSERT INTO status_transitions (from_status, to_status) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		if _, err := q.Exec(ctx, query, status.Name, to); err != nil {
+			slog.Error("database query failed: add status transition", "error", err, "status", status.Name, "to", to)
+			return pgError(err)
+		}
+	}
+
+	return nil
+}
+
+// pgStatusChange проверяет изменение статуса задачи из from в to, см. StatusStore
+func pgStatusChange(ctx context.Context, q pgQuerier, from, to string) (statusChange, error) {
+	c := statusChange{from: from, to: to}
+
+	query := fmt.Sprintf(statusChangeQuery, "$1", "$2")
+	if err := q.QueryRow(ctx, query, from, to).Scan(&c.wasDone, &c.done, &c.allowed); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("status not found", "status", to)
+			return c, ErrStatusNotFound
+		}
+
+		slog.Error("database query failed: check status change", "error", err, "from", from, "to", to)
+
+		return c, err
+	}
+
+	return c, c.check()
+}
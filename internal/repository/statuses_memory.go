@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// defaultStatuses возвращает встроенный процесс, который создают миграции баз данных: new, in_progress и done
+// с переходами между любыми двумя статусами
+func defaultStatuses() map[string]models.Status {
+	statuses := map[string]models.Status{
+		models.DefaultTaskStatus: {Name: models.DefaultTaskStatus, Category: models.TodoStatusCategory, Position: 1},
+		"in_progress":            {Name: "in_progress", Category: models.DoingStatusCategory, Position: 2},
+		models.DoneTaskStatus:    {Name: models.DoneTaskStatus, Category: models.DoneStatusCategory, Position: 3},
+	}
+
+	for name, s := range statuses {
+		for to := range statuses {
+			if to != name {
+				s.Transitions = append(s.Transitions, to)
+			}
+		}
+		statuses[name] = s
+	}
+
+	return statuses
+}
+
+func (r *MemoryTaskRepository) ListStatuses(ctx context.Context) ([]models.Status, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: list statuses")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := []models.Status{}
+	for _, s := range r.statuses {
+		s.Transitions = slices.Clone(s.Transitions)
+		sort.Slice(s.Transitions, func(i, j int) bool {
+			return r.statusLess(s.Transitions[i], s.Transitions[j])
+		})
+		statuses = append(statuses, s)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return r.statusLess(statuses[i].Name, statuses[j].Name)
+	})
+
+	return statuses, nil
+}
+
+func (r *MemoryTaskRepository) CreateStatus(ctx context.Context, status *models.Status) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: create status", "name", status.Name, "category", status.Category)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.statuses[status.Name]; ok {
+		return fmt.Errorf("%w: status %q already exists", ErrConflict, status.Name)
+	}
+
+	if err := r.checkTransitions(status); err != nil {
+		return err
+	}
+
+	if status.Position == 0 {
+		for _, s := range r.statuses {
+			status.Position = max(status.Position, s.Position)
+		}
+		status.Position++
+	}
+
+	r.statuses[status.Name] = memoryStatus(status)
+
+	return nil
+}
+
+func (r *MemoryTaskRepository) UpdateStatus(ctx context.Context, status *models.Status) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: update status", "name", status.Name, "category", status.Category)
+	}
+
+	if err := builtinCategory(status.Name, status.Category); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.statuses[status.Name]
+	if !ok {
+		slog.Warn("status not found for update", "status", status.Name)
+		return ErrNotFound
+	}
+
+	if err := r.checkTransitions(status); err != nil {
+		return err
+	}
+
+	if status.Position == 0 {
+		status.Position = current.Position
+	}
+
+	r.statuses[status.Name] = memoryStatus(status)
+
+	if current.Category != status.Category {
+		// прогресс подзадач родителей зависит от категории статуса
+		parents := map[int]bool{}
+		for _, t := range r.tasks {
+			if t.Status == status.Name && t.ParentID != nil && t.DeletedAt == nil {
+				parents[*t.ParentID] = true
+			}
+		}
+
+		for id := range parents {
+			parent := r.tasks[id]
+			parent.Version++
+			r.tasks[id] = parent
+		}
+
+		r.countSubtasks()
+	}
+
+	return nil
+}
+
+func (r *MemoryTaskRepository) DeleteStatus(ctx context.Context, name string) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: delete status", "name", name)
+	}
+
+	if isBuiltinStatus(name) {
+		slog.Warn("built-in status deletion", "status", name)
+		return ErrStatusBuiltin
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.statuses[name]; !ok {
+		slog.Warn("status not found for deletion", "status", name)
+		return ErrNotFound
+	}
+
+	for _, t := range r.tasks {
+		if t.Status == name {
+			slog.Warn("status is used by tasks", "status", name)
+			return ErrStatusInUse
+		}
+	}
+
+	delete(r.statuses, name)
+
+	for from, s := range r.statuses {
+		if slices.Contains(s.Transitions, name) {
+			s.Transitions = without(s.Transitions, name)
+			r.statuses[from] = s
+		}
+	}
+
+	return nil
+}
+
+// memoryStatus возвращает копию статуса, не разделяющую переходы с вызывающим
+func memoryStatus(status *models.Status) models.Status {
+	s := *status
+	s.Transitions = slices.Clone(status.Transitions)
+
+	return s
+}
+
+// checkTransitions проверяет, что целевые статусы переходов существуют. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) checkTransitions(status *models.Status) error {
+	for _, to := range status.Transitions {
+		if _, ok := r.statuses[to]; !ok {
+			slog.Warn("transition status not found", "status", status.Name, "to", to)
+			return ErrStatusNotFound
+		}
+	}
+
+	return nil
+}
+
+// statusLess сравнивает статусы по позиции и названию. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) statusLess(a, b string) bool {
+	pa, pb := r.statuses[a].Position, r.statuses[b].Position
+	if pa != pb {
+		return pa < pb
+	}
+
+	return a < b
+}
+
+// isDone сообщает, что статус относится к категории done. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) isDone(status string) bool {
+	return r.statuses[status].Category == models.DoneStatusCategory
+}
+
+// statusChange проверяет изменение статуса задачи из from в to, см. pgStatusChange.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) statusChange(from, to string) (statusChange, error) {
+	c := statusChange{from: from, to: to}
+
+	s, ok := r.statuses[to]
+	if !ok {
+		slog.Warn("status not found", "status", to)
+		return c, ErrStatusNotFound
+	}
+
+	c.wasDone = r.isDone(from)
+	c.done = s.Category == models.DoneStatusCategory
+	c.allowed = slices.Contains(r.statuses[from].Transitions, to)
+
+	return c, c.check()
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// В SQLite колонка tasks.status не ссылается на statuses, поэтому существование статуса проверяет только хранилище
+
+func (r *SQLiteTaskRepository) ListStatuses(ctx context.Context) ([]models.Status, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: list statuses")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT name, category, position FROM statuses ORDER BY position, name`)
+	if err != nil {
+		slog.Error("sqlite query failed: list statuses", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := []models.Status{}
+	byName := map[string]int{}
+
+	for rows.Next() {
+		s := models.Status{Transitions: []string{}}
+		if err := rows.Scan(&s.Name, &s.Category, &s.Position); err != nil {
+			slog.Error("failed to scan status row", "error", err)
+			return nil, err
+		}
+
+		byName[s.Name] = len(statuses)
+		statuses = append(statuses, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: list statuses", "error", err)
+		return nil, err
+	}
+
+	rows, err = r.db.QueryContext(ctx, statusTransitionsQuery)
+	if err != nil {
+		slog.Error("sqlite query failed: list status transitions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from, to string
+		if err := rows.Scan(&from, &to); err != nil {
+			slog.Error("failed to scan status transition row", "error", err)
+			return nil, err
+		}
+
+		if i, ok := byName[from]; ok {
+			statuses[i].Transitions = append(statuses[i].Transitions, to)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: list status transitions", "error", err)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: list statuses", "count", len(statuses))
+	}
+
+	return statuses, nil
+}
+
+func (r *SQLiteTaskRepository) CreateStatus(ctx context.Context, status *models.Status) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: create status", "name", status.Name, "category", status.Category)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: create status", "error", err, "status", status.Name)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO statuses (name, category, position)
+		VALUES (?1, ?2, COALESCE(NULLIF(?3, 0), (SELECT COALESCE(max(position), 0) + 1 FROM statuses)))
+		RETURNING position`
+
+	if err := tx.QueryRowContext(ctx, query, status.Name, status.Category, status.Position).Scan(&status.Position); err != nil {
+		slog.Error("sqlite query failed: create status", "error", err, "status", status.Name)
+		return sqliteError(err)
+	}
+
+	if err := sqliteSetTransitions(ctx, tx, status); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: create status", "error", err, "status", status.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) UpdateStatus(ctx context.Context, status *models.Status) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: update status", "name", status.Name, "category", status.Category)
+	}
+
+	if err := builtinCategory(status.Name, status.Category); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: update status", "error", err, "status", status.Name)
+		return err
+	}
+	defer tx.Rollback()
+
+	var category string
+	if err := tx.QueryRowContext(ctx, `SELECT category FROM statuses WHERE name = ?`, status.Name).Scan(&category); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("status not found for update", "status", status.Name)
+			return ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: update status", "error", err, "status", status.Name)
+
+		return err
+	}
+
+	query := `
+		UPDATE statuses SET category = ?2, position = COALESCE(NULLIF(?3, 0), position)
+		WHERE name = ?1
+		RETURNING position`
+
+	if err := tx.QueryRowContext(ctx, query, status.Name, status.Category, status.Position).Scan(&status.Position); err != nil {
+		slog.Error("sqlite query failed: update status", "error", err, "status", status.Name)
+		return sqliteError(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM status_transitions WHERE from_status = ?`, status.Name); err != nil {
+		slog.Error("sqlite query failed: update status", "error", err, "status", status.Name)
+		return err
+	}
+
+	if err := sqliteSetTransitions(ctx, tx, status); err != nil {
+		return err
+	}
+
+	if category != status.Category {
+		query = `
+			UPDATE tasks SET version = version + 1
+			WHERE id IN (SELECT parent_id FROM tasks WHERE status = ? AND parent_id IS NOT NULL AND deleted_at IS NULL)`
+
+		if _, err := tx.ExecContext(ctx, query, status.Name); err != nil {
+			slog.Error("sqlite query failed: touch parent tasks", "error", err, "status", status.Name)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: update status", "error", err, "status", status.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) DeleteStatus(ctx context.Context, name string) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: delete status", "name", name)
+	}
+
+	if isBuiltinStatus(name) {
+		slog.Warn("built-in status deletion", "status", name)
+		return ErrStatusBuiltin
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: delete status", "error", err, "status", name)
+		return err
+	}
+	defer tx.Rollback()
+
+	var used bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE status = ?)`, name).Scan(&used); err != nil {
+		slog.Error("sqlite query failed: delete status", "error", err, "status", name)
+		return err
+	}
+
+	if used {
+		slog.Warn("status is used by tasks", "status", name)
+		return ErrStatusInUse
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM statuses WHERE name = ?`, name)
+	if err != nil {
+		slog.Error("sqlite query failed: delete status", "error", err, "status", name)
+		return sqliteError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: delete status", "error", err, "status", name)
+		return err
+	}
+
+	if n == 0 {
+		slog.Warn("status not found for deletion", "status", name)
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: delete status", "error", err, "status", name)
+		return err
+	}
+
+	return nil
+}
+
+// sqliteSetTransitions добавляет переходы из статуса status, см. pgSetTransitions
+func sqliteSetTransitions(ctx context.Context, q sqliteQuerier, status *models.Status) error {
+	for _, to := range status.Transitions {
+		var exists int
+		if err := q.QueryRowContext(ctx, `SELECT 1 FROM statuses WHERE name = ?`, to).Scan(&exists); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				slog.Warn("transition status not found", "status", status.Name, "to", to)
+				return ErrStatusNotFound
+			}
+
+			slog.Error("sqlite query failed: check transition status", "error", err, "to", to)
+
+			return err
+		}
+
+		query := `INSERT INTO status_transitions (from_status, to_status) VALUES (?, ?) ON CONFLICT DO NOTHING`
+		if _, err := q.ExecContext(ctx, query, status.Name, to); err != nil {
+			slog.Error("sqlite query failed: add status transition", "error", err, "status", status.Name, "to", to)
+			return sqliteError(err)
+		}
+	}
+
+	return nil
+}
+
+// sqliteStatusChange проверяет изменение статуса задачи из from в to, см. pgStatusChange
+func sqliteStatusChange(ctx context.Context, q sqliteQuerier, from, to string) (statusChange, error) {
+	c := statusChange{from: from, to: to}
+
+	query := fmt.Sprintf(statusChangeQuery, "?1", "?2")
+	if err := q.QueryRowContext(ctx, query, from, to).Scan(&c.wasDone, &c.done, &c.allowed); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("status not found", "status", to)
+			return c, ErrStatusNotFound
+		}
+
+		slog.Error("sqlite query failed: check status change", "error", err, "from", from, "to", to)
+
+		return c, err
+	}
+
+	return c, c.check()
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// restrictNew оставляет из статуса по умолчанию только переход в in_progress
+func restrictNew(t *testing.T, r *MemoryTaskRepository) {
+	t.Helper()
+
+	err := r.UpdateStatus(context.Background(), &models.Status{
+		Name:        models.DefaultTaskStatus,
+		Category:    models.TodoStatusCategory,
+		Transitions: []string{"in_progress"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+}
+
+func TestStatusTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantErr    error
+		wantStatus string
+	}{
+		{"allowed transition", "in_progress", nil, "in_progress"},
+		{"transition not allowed", models.DoneTaskStatus, ErrStatusTransition, models.DefaultTaskStatus},
+		{"unknown status", "missing", ErrStatusNotFound, models.DefaultTaskStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := NewMemoryTaskRepository()
+			restrictNew(t, r)
+			mustCreate(t, r, "a")
+
+			_, err := r.Update(ctx, 1, map[string]any{"status": tt.status}, 0, UpdateOptions{})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Update(status=%q) error = %v, want %v", tt.status, err, tt.wantErr)
+			}
+
+			if got := mustGet(t, r, 1).Status; got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+
+			history, err := r.History(ctx, 1)
+			if err != nil {
+				t.Fatalf("History() error = %v", err)
+			}
+
+			// создание задачи записывает в историю ее начальный статус
+			want := 1
+			if tt.wantErr == nil {
+				want = 2
+			}
+
+			if len(history) != want {
+				t.Fatalf("len(history) = %d, want %d: %+v", len(history), want, history)
+			}
+
+			last := history[len(history)-1]
+			if tt.wantErr == nil && (last.From == nil || *last.From != models.DefaultTaskStatus || last.To != tt.status) {
+				t.Errorf("last change = %v -> %q, want %q -> %q", last.From, last.To, models.DefaultTaskStatus, tt.status)
+			}
+		})
+	}
+}
+
+func TestDeleteStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		// use задача в статусе review: active - активная, deleted - в корзине, пустая строка - задачи нет
+		use    string
+		status string
+		want   error
+	}{
+		{"unused status", "", "review", nil},
+		{"status of active task", "active", "review", ErrStatusInUse},
+		{"status of task in trash", "deleted", "review", ErrStatusInUse},
+		{"built-in status", "", models.DefaultTaskStatus, ErrStatusBuiltin},
+		{"missing status", "", "missing", ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := NewMemoryTaskRepository()
+
+			review := &models.Status{Name: "review", Category: models.DoingStatusCategory}
+			if err := r.CreateStatus(ctx, review); err != nil {
+				t.Fatalf("CreateStatus() error = %v", err)
+			}
+
+			if tt.use != "" {
+				if err := r.Create(ctx, &models.Task{Title: "a", Status: "review"}); err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+			}
+			if tt.use == "deleted" {
+				if err := r.Delete(ctx, 1, 0); err != nil {
+					t.Fatalf("Delete() error = %v", err)
+				}
+			}
+
+			err := r.DeleteStatus(ctx, tt.status)
+			if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+				t.Fatalf("DeleteStatus(%q) error = %v, want %v", tt.status, err, tt.want)
+			}
+
+			statuses, err := r.ListStatuses(ctx)
+			if err != nil {
+				t.Fatalf("ListStatuses() error = %v", err)
+			}
+
+			exists := false
+			for _, s := range statuses {
+				exists = exists || s.Name == tt.status
+			}
+
+			if wantExists := tt.want != nil && tt.status != "missing"; exists != wantExists {
+				t.Errorf("status %q exists = %v, want %v", tt.status, exists, wantExists)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrParentNotFound возвращается, когда задачу делают подзадачей несуществующей или удаленной задачи
+	ErrParentNotFound = fmt.Errorf("%w: parent task not found", ErrInvalid)
+	// ErrParentCycle возвращается, когда задачу делают подзадачей ее самой или ее потомка
+	ErrParentCycle = fmt.Errorf("%w: parent task is the task itself or its subtask", ErrInvalid)
+	// ErrParentDeleted возвращается при восстановлении подзадачи, родитель которой находится в корзине
+	ErrParentDeleted = fmt.Errorf("%w: parent task is deleted", ErrConflict)
+)
+
+// SubtaskStore описывает иерархию задач.
+// Родителем задачи может быть только активная задача, которая не является ею самой или ее потомком:
+// иначе TaskStore возвращает ErrParentNotFound или ErrParentCycle. Перевод задачи в статус категории done
+// переводит в тот же статус все ее невыполненные подзадачи на всех уровнях. Delete перемещает в корзину задачу вместе
+// со всеми активными потомками, а Restore возвращает задачу вместе с потомками, удаленными одновременно с ней.
+// Подзадачу, родитель которой находится в корзине, восстановить нельзя: Restore возвращает ErrParentDeleted.
+// Purge удаляет задачу вместе со всеми потомками. Изменение, которое меняет прогресс подзадач родителя,
+// увеличивает версию родителя
+type SubtaskStore interface {
+	// Subtree возвращает активную задачу и всех ее активных потомков в порядке создания
+	Subtree(ctx context.Context, id int) ([]models.Task, error)
+}
+
+// updatedParentID возвращает родителя из изменений задачи, если он передан и не пуст.
+// Значение parent_id в updates - *int или nil
+func updatedParentID(updates map[string]any) (int, bool) {
+	if id, ok := updates["parent_id"].(*int); ok && id != nil {
+		return *id, true
+	}
+
+	return 0, false
+}
+
+// relatedChanged сообщает, что изменение задачи меняет представление связанных с ней задач:
+// прогресс подзадач родителя зависит от статуса задачи и от того, чьей подзадачей она является,
+// а ссылки на задачу в связях блокировки содержат ее название и статус
+func relatedChanged(before, after *models.Task) bool {
+	return before.Status != after.Status || before.Title != after.Title || previousParent(before, after) != nil
+}
+
+// previousParent возвращает прежнего родителя задачи, если изменение перенесло ее к другому родителю
+func previousParent(before, after *models.Task) []int {
+	if before.ParentID == nil || (after.ParentID != nil && *after.ParentID == *before.ParentID) {
+		return nil
+	}
+
+	return []int{*before.ParentID}
+}
+
+// subtreeCTE возвращает рекурсивное табличное выражение subtree(id) с задачами, выбранными условием root,
+// и их потомками, для которых выполняется условие descend над колонками потомка child
+func subtreeCTE(root, descend string) string {
+	return `WITH RECURSIVE subtree(id) AS (
+		SELECT id FROM tasks WHERE ` + root + `
+		UNION
+		SELECT child.id FROM tasks child JOIN subtree ON child.parent_id = subtree.id WHERE ` + descend + `
+	) `
+}
+
+func (r *TaskRepository) Subtree(ctx context.Context, id int) ([]models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: get task subtree", "id", id)
+	}
+
+	query := subtreeCTE("id = $1 AND deleted_at IS NULL", "child.deleted_at IS NULL") +
+		`SELECT ` + taskColumns + ` FROM tasks WHERE id IN (SELECT id FROM subtree) ORDER BY created_at, id`
+
+	rows, err := r.dbPool.Query(ctx, query, id)
+	if err != nil {
+		slog.Error("database query failed: get task subtree", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			slog.Error("failed to scan task row", "error", err)
+			return nil, err
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: get task subtree", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		slog.Warn("task not found", "task_id", id)
+		return nil, ErrNotFound
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: get task subtree", "id", id, "count", len(tasks))
+	}
+
+	return tasks, nil
+}
+
+// pgCheckParent проверяет, что задачу taskID можно сделать подзадачей parentID: родитель активен
+// и не является самой задачей или ее потомком. Для новой задачи taskID равен 0
+func pgCheckParent(ctx context.Context, q pgQuerier, parentID, taskID int) error {
+	query := `
+		WITH RECURSIVE ancestors(id, parent_id) AS (
+			SELECT id, parent_id FROM tasks WHERE id = $1
+			UNION
+			SELECT tasks.id, tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR SHARE`
+
+	var cycle bool
+	if err := q.QueryRow(ctx, query, parentID, taskID).Scan(&cycle); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("parent task not found", "parent_id", parentID, "task_id", taskID)
+			return ErrParentNotFound
+		}
+
+		slog.Error("database query failed: check parent task", "error", err, "parent_id", parentID)
+
+		return err
+	}
+
+	if cycle {
+		slog.Warn("parent task is a subtask of the task", "parent_id", parentID, "task_id", taskID)
+		return ErrParentCycle
+	}
+
+	return nil
+}
+
+// pgCompleteSubtasks переводит невыполненные активные подзадачи задачи id на всех уровнях в статус done
+// категории done, если сама задача еще не выполнена, и возвращает их ID. Переходы процесса для подзадач
+// не проверяются, для повторяющихся подзадач создаются следующие повторения. Вызывается до изменения
+// статуса задачи
+func pgCompleteSubtasks(ctx context.Context, q pgQuerier, id int, done string) ([]int, error) {
+	subtree := subtreeCTE(
+		"parent_id = $1 AND deleted_at IS NULL AND EXISTS (SELECT 1 FROM tasks p WHERE p.id = $1 AND p.status NOT IN "+doneStatuses+")",
+		"child.deleted_at IS NULL",
+	)
+	open := `id IN (SELECT id FROM subtree) AND status NOT IN ` + doneStatuses
+
+	before, err := pgQueryTasks(ctx, q, subtree+`SELECT `+taskColumns+` FROM tasks WHERE `+open+` ORDER BY id`, id)
+	if err != nil {
+		slog.Error("database query failed: get open subtasks", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if len(before) == 0 {
+		return []int{}, nil
+	}
+
+	// история записывается до изменения, пока в задачах хранятся прежние статусы
+	query := subtree + `
+		INSERT INTO task_status_history (task_id, from_status, to_status, changed_at, actor)
+		SELECT id, status, $2, now(), NULLIF($3, '') FROM tasks WHERE ` + open + `
+		ORDER BY id`
+
+	if _, err := q.Exec(ctx, query, id, done, requestinfo.From(ctx).Actor); err != nil {
+		slog.Error("database query failed: record subtasks status", "error", err, "task_id", id)
+		return nil, pgError(err)
+	}
+
+	query = subtree + `
+		UPDATE tasks
+		SET status = $2, updated_at = now(), version = version + 1, ` + statusTimesSet("$2", "now()") + `
+		WHERE ` + open + `
+		RETURNING id`
+
+	ids, err := pgQueryIDs(ctx, q, query, id, done)
+	if err != nil {
+		slog.Error("database query failed: complete subtasks", "error", err, "task_id", id)
+		return nil, pgError(err)
+	}
+
+	after, err := pgGetTasks(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pgAuditTasks(ctx, q, models.UpdateAuditAction, before, after); err != nil {
+		return nil, err
+	}
+
+	if err := pgSpawnOccurrences(ctx, q, after); err != nil {
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: complete subtasks", "id", id, "rows_affected", len(ids))
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+func (r *MemoryTaskRepository) Subtree(ctx context.Context, id int) ([]models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: get task subtree", "id", id)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.activeTask(id); !ok {
+		slog.Warn("task not found", "task_id", id)
+		return nil, ErrNotFound
+	}
+
+	ids := r.subtree([]int{id}, isActiveTask)
+
+	tasks := make([]models.Task, len(ids))
+	for i, taskID := range ids {
+		tasks[i] = r.tasks[taskID]
+	}
+
+	keys := orderKeys(nil)
+	sort.Slice(tasks, func(i, j int) bool {
+		return compareTasks(&tasks[i], &tasks[j], keys) < 0
+	})
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("memory query completed: get task subtree", "id", id, "count", len(tasks))
+	}
+
+	return tasks, nil
+}
+
+func isActiveTask(t models.Task) bool {
+	return t.DeletedAt == nil
+}
+
+// subtree возвращает задачи roots и их потомков, для которых выполняется descend, аналог subtreeCTE.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) subtree(roots []int, descend func(t models.Task) bool) []int {
+	children := map[int][]int{}
+	for id, t := range r.tasks {
+		if t.ParentID != nil {
+			children[*t.ParentID] = append(children[*t.ParentID], id)
+		}
+	}
+
+	seen := map[int]bool{}
+	ids := make([]int, 0, len(roots))
+
+	for _, id := range roots {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] && descend(r.tasks[child]) {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+
+	return ids
+}
+
+// checkParent проверяет, что задачу taskID можно сделать подзадачей parentID, см. pgCheckParent.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) checkParent(parentID, taskID int) error {
+	if _, ok := r.activeTask(parentID); !ok {
+		slog.Warn("parent task not found", "parent_id", parentID, "task_id", taskID)
+		return ErrParentNotFound
+	}
+
+	for id := &parentID; id != nil; id = r.tasks[*id].ParentID {
+		if *id == taskID {
+			slog.Warn("parent task is a subtask of the task", "parent_id", parentID, "task_id", taskID)
+			return ErrParentCycle
+		}
+	}
+
+	return nil
+}
+
+// completeSubtasks переводит активные подзадачи задачи id в статус done и возвращает их ID, см. pgCompleteSubtasks.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) completeSubtasks(ctx context.Context, id int, done string, now time.Time) []int {
+	completed := []int{}
+	if r.isDone(r.tasks[id].Status) {
+		return completed
+	}
+
+	var before, after []models.Task
+	for _, taskID := range r.subtree([]int{id}, isActiveTask)[1:] {
+		t := r.tasks[taskID]
+		if r.isDone(t.Status) {
+			continue
+		}
+
+		before = append(before, t)
+		from := t.Status
+		t.Status = done
+		r.recordStatus(ctx, &t, from, now)
+		t.UpdatedAt = now
+		t.Version++
+		r.tasks[taskID] = t
+		completed = append(completed, taskID)
+		after = append(after, t)
+	}
+
+	r.recordAuditTasks(ctx, models.UpdateAuditAction, before, after, now)
+
+	for _, taskID := range completed {
+		t := r.tasks[taskID]
+		r.spawnOccurrence(ctx, &t, now)
+	}
+
+	return completed
+}
+
+// countSubtasks пересчитывает прогресс подзадач, который SQL хранилища вычисляют при чтении.
+// Вызывается после изменений, затрагивающих родителя, статус или наличие в корзине задач.
+// Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) countSubtasks() {
+	progress := map[int]models.SubtaskProgress{}
+	for _, t := range r.tasks {
+		if t.ParentID == nil || t.DeletedAt != nil {
+			continue
+		}
+
+		p := progress[*t.ParentID]
+		p.Total++
+		if r.isDone(t.Status) {
+			p.Done++
+		}
+		progress[*t.ParentID] = p
+	}
+
+	for id, t := range r.tasks {
+		if t.Subtasks != progress[id] {
+			t.Subtasks = progress[id]
+			r.tasks[id] = t
+		}
+	}
+}
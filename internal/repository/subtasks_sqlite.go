@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+)
+
+func (r *SQLiteTaskRepository) Subtree(ctx context.Context, id int) ([]models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: get task subtree", "id", id)
+	}
+
+	query := subtreeCTE("id = ? AND deleted_at IS NULL", "child.deleted_at IS NULL") +
+		`SELECT ` + taskColumns + ` FROM tasks WHERE id IN (SELECT id FROM subtree) ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		slog.Error("sqlite query failed: get task subtree", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			slog.Error("failed to scan task row", "error", err)
+			return nil, err
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: get task subtree", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		slog.Warn("task not found", "task_id", id)
+		return nil, ErrNotFound
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: get task subtree", "id", id, "count", len(tasks))
+	}
+
+	return tasks, nil
+}
+
+// sqliteCheckParent проверяет, что задачу taskID можно сделать подзадачей parentID, см. pgCheckParent
+func sqliteCheckParent(ctx context.Context, q sqliteQuerier, parentID, taskID int) error {
+	query := `
+		WITH RECURSIVE ancestors(id, parent_id) AS (
+			SELECT id, parent_id FROM tasks WHERE id = ?
+			UNION
+			SELECT tasks.id, tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = ?)
+		FROM tasks
+		WHERE id = ? AND deleted_at IS NULL`
+
+	var cycle bool
+	if err := q.QueryRowContext(ctx, query, parentID, taskID, parentID).Scan(&cycle); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("parent task not found", "parent_id", parentID, "task_id", taskID)
+			return ErrParentNotFound
+		}
+
+		slog.Error("sqlite query failed: check parent task", "error", err, "parent_id", parentID)
+
+		return err
+	}
+
+	if cycle {
+		slog.Warn("parent task is a subtask of the task", "parent_id", parentID, "task_id", taskID)
+		return ErrParentCycle
+	}
+
+	return nil
+}
+
+// sqliteCompleteSubtasks выполняет активные подзадачи задачи id и возвращает их ID, см. pgCompleteSubtasks
+func sqliteCompleteSubtasks(ctx context.Context, q sqliteQuerier, id int, done string) ([]int, error) {
+	subtree := subtreeCTE(
+		"parent_id = ?1 AND deleted_at IS NULL AND EXISTS (SELECT 1 FROM tasks p WHERE p.id = ?1 AND p.status NOT IN "+doneStatuses+")",
+		"child.deleted_at IS NULL",
+	)
+	open := `id IN (SELECT id FROM subtree) AND status NOT IN ` + doneStatuses
+	now := time.Now().UTC()
+
+	before, err := sqliteQueryTasks(ctx, q, subtree+`SELECT `+taskColumns+` FROM tasks WHERE `+open+` ORDER BY id`, id)
+	if err != nil {
+		slog.Error("sqlite query failed: get open subtasks", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if len(before) == 0 {
+		return []int{}, nil
+	}
+
+	// история записывается до изменения, пока в задачах хранятся прежние статусы
+	query := subtree + `
+		INSERT INTO task_status_history (task_id, from_status, to_status, changed_at, actor)
+		SELECT id, status, ?2, ?3, NULLIF(?4, '') FROM tasks WHERE ` + open + `
+		ORDER BY id`
+
+	if _, err := q.ExecContext(ctx, query, id, done, now, requestinfo.From(ctx).Actor); err != nil {
+		slog.Error("sqlite query failed: record subtasks status", "error", err, "task_id", id)
+		return nil, sqliteError(err)
+	}
+
+	query = subtree + `
+		UPDATE tasks
+		SET status = ?2, updated_at = ?3, version = version + 1, ` + statusTimesSet("?2", "?3") + `
+		WHERE ` + open + `
+		RETURNING id`
+
+	ids, err := sqliteQueryIDs(ctx, q, query, id, done, now)
+	if err != nil {
+		slog.Error("sqlite query failed: complete subtasks", "error", err, "task_id", id)
+		return nil, sqliteError(err)
+	}
+
+	after, err := sqliteGetTasks(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqliteAuditTasks(ctx, q, models.UpdateAuditAction, before, after, now); err != nil {
+		return nil, err
+	}
+
+	if err := sqliteSpawnOccurrences(ctx, q, after); err != nil {
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("sqlite query completed: complete subtasks", "id", id, "rows_affected", len(ids))
+	}
+
+	return ids, nil
+}
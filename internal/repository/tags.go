@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrTagNotFound возвращается, когда к задаче прикрепляют или открепляют несуществующую метку
+var ErrTagNotFound = fmt.Errorf("%w: tag not found", ErrNotFound)
+
+// TagStore описывает хранилище меток.
+// Метки входят в представление задачи, поэтому любое изменение меток задачи, включая переименование
+// и удаление метки, увеличивает версию задачи
+type TagStore interface {
+	// ListTags возвращает все метки, упорядоченные по названию
+	ListTags(ctx context.Context) ([]models.Tag, error)
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	RenameTag(ctx context.Context, id int, name string) (*models.Tag, error)
+	// DeleteTag удаляет метку и открепляет ее от всех задач
+	DeleteTag(ctx context.Context, id int) error
+	// AttachTag прикрепляет метку к активной задаче. Повторное прикрепление не изменяет задачу
+	AttachTag(ctx context.Context, taskID int, name string) (*models.Task, error)
+	// DetachTag открепляет метку от активной задачи. Если метка не была прикреплена, задача не изменяется
+	DetachTag(ctx context.Context, taskID int, name string) (*models.Task, error)
+}
+
+func (r *TaskRepository) ListTags(ctx context.Context) ([]models.Tag, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: list tags")
+	}
+
+	rows, err := r.dbPool.Query(ctx, `SELECT id, name, created_at FROM tags ORDER BY name`)
+	if err != nil {
+		slog.Error("database query failed: list tags", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+			slog.Error("failed to scan tag row", "error", err)
+			return nil, err
+		}
+
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: list tags", "error", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *TaskRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: create tag", "name", tag.Name)
+	}
+
+	query := `INSERT INTO tags (name) VALUES ($1) RETURNING id, created_at`
+	if err := r.dbPool.QueryRow(ctx, query, tag.Name).Scan(&tag.ID, &tag.CreatedAt); err != nil {
+		slog.Error("database query failed: create tag", "error", err, "name", tag.Name)
+		return pgError(err)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) RenameTag(ctx context.Context, id int, name string) (*models.Tag, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: rename tag", "id", id, "name", name)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: rename tag", "error", err, "tag_id", id)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tagged, err := pgTaggedTasks(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.Tag{}
+	query := `UPDATE tags SET name = $1 WHERE id = $2 RETURNING id, name, created_at`
+	if err := tx.QueryRow(ctx, query, name, id).Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("tag not found for rename", "tag_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: rename tag", "error", err, "tag_id", id)
+
+		return nil, pgError(err)
+	}
+
+	if err := pgTouchTaggedTasks(ctx, tx, tagged); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: rename tag", "error", err, "tag_id", id)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *TaskRepository) DeleteTag(ctx context.Context, id int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: delete tag", "id", id)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: delete tag", "error", err, "tag_id", id)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tagged, err := pgTaggedTasks(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := tx.Exec(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		slog.Error("database query failed: delete tag", "error", err, "tag_id", id)
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		slog.Warn("tag not found for deletion", "tag_id", id)
+		return ErrNotFound
+	}
+
+	if err := pgTouchTaggedTasks(ctx, tx, tagged); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: delete tag", "error", err, "tag_id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) AttachTag(ctx context.Context, taskID int, name string) (*models.Task, error) {
+	return r.changeTaskTag(ctx, taskID, name, `INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`)
+}
+
+func (r *TaskRepository) DetachTag(ctx context.Context, taskID int, name string) (*models.Task, error) {
+	return r.changeTaskTag(ctx, taskID, name, `DELETE FROM task_tags WHERE task_id = $1 AND tag_id = $2`)
+}
+
+// changeTaskTag выполняет запрос change над связью задачи и метки и, если связь изменилась,
+// увеличивает версию задачи
+func (r *TaskRepository) changeTaskTag(ctx context.Context, taskID int, name, change string) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: change task tag", "task_id", taskID, "tag", name)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: change task tag", "error", err, "task_id", taskID)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tagID int
+	if err := tx.QueryRow(ctx, `SELECT id FROM tags WHERE name = $1`, name).Scan(&tagID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("tag not found", "tag", name)
+			return nil, ErrTagNotFound
+		}
+
+		slog.Error("database query failed: change task tag", "error", err, "tag", name)
+
+		return nil, err
+	}
+
+	before, err := pgLockTask(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if before == nil {
+		slog.Warn("task not found for tag change", "task_id", taskID)
+		return nil, ErrNotFound
+	}
+
+	cmd, err := tx.Exec(ctx, change, taskID, tagID)
+	if err != nil {
+		slog.Error("database query failed: change task tag", "error", err, "task_id", taskID, "tag", name)
+		return nil, err
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1`
+	if cmd.RowsAffected() > 0 {
+		query = `UPDATE tasks SET updated_at = now(), version = version + 1 WHERE id = $1 RETURNING ` + taskColumns
+	}
+
+	t := &models.Task{}
+	if err := scanTask(tx.QueryRow(ctx, query, taskID), t); err != nil {
+		slog.Error("database query failed: change task tag", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	if err := pgAudit(ctx, tx, taskID, before, t); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: change task tag", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// pgTaggedTasks возвращает задачи с меткой tagID, в том числе из корзины
+func pgTaggedTasks(ctx context.Context, q pgQuerier, tagID int) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = $1) ORDER BY id`
+
+	tasks, err := pgQueryTasks(ctx, q, query, tagID)
+	if err != nil {
+		slog.Error("database query failed: get tagged tasks", "error", err, "tag_id", tagID)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// pgTouchTaggedTasks увеличивает версию задач tagged после изменения их метки, чтобы оно изменило их ETag,
+// и записывает изменение меток задач в журнал аудита
+func pgTouchTaggedTasks(ctx context.Context, q pgQuerier, tagged []models.Task) error {
+	ids := make([]int, len(tagged))
+	for i, t := range tagged {
+		ids[i] = t.ID
+	}
+
+	if _, err := q.Exec(ctx, `UPDATE tasks SET version = version + 1 WHERE id = ANY($1)`, ids); err != nil {
+		slog.Error("database query failed: touch tagged tasks", "error", err, "task_ids", ids)
+		return err
+	}
+
+	after, err := pgGetTasks(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+
+	return pgAuditTasks(ctx, q, models.UpdateAuditAction, tagged, after)
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+// Задачи хранят названия своих меток в models.Task.Tags. Срезы меток разделяются между копиями задачи,
+// поэтому они никогда не изменяются на месте: при каждом изменении создается новый срез
+
+func (r *MemoryTaskRepository) ListTags(ctx context.Context) ([]models.Tag, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: list tags")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tags := []models.Tag{}
+	for _, t := range r.tags {
+		tags = append(tags, t)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags, nil
+}
+
+func (r *MemoryTaskRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: create tag", "name", tag.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tagByName(tag.Name); ok {
+		return fmt.Errorf("%w: tag %q already exists", ErrConflict, tag.Name)
+	}
+
+	tag.ID = r.nextTagID
+	tag.CreatedAt = time.Now().UTC()
+
+	r.tags[tag.ID] = *tag
+	r.nextTagID++
+
+	return nil
+}
+
+func (r *MemoryTaskRepository) RenameTag(ctx context.Context, id int, name string) (*models.Tag, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: rename tag", "id", id, "name", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tag, ok := r.tags[id]
+	if !ok {
+		slog.Warn("tag not found for rename", "tag_id", id)
+		return nil, ErrNotFound
+	}
+
+	if other, ok := r.tagByName(name); ok && other.ID != id {
+		return nil, fmt.Errorf("%w: tag %q already exists", ErrConflict, name)
+	}
+
+	r.retagTasks(ctx, tag.Name, func(tags []string) []string {
+		return append(without(tags, tag.Name), name)
+	})
+
+	tag.Name = name
+	r.tags[id] = tag
+
+	return &tag, nil
+}
+
+func (r *MemoryTaskRepository) DeleteTag(ctx context.Context, id int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: delete tag", "id", id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tag, ok := r.tags[id]
+	if !ok {
+		slog.Warn("tag not found for deletion", "tag_id", id)
+		return ErrNotFound
+	}
+
+	r.retagTasks(ctx, tag.Name, func(tags []string) []string {
+		return without(tags, tag.Name)
+	})
+
+	delete(r.tags, id)
+
+	return nil
+}
+
+func (r *MemoryTaskRepository) AttachTag(ctx context.Context, taskID int, name string) (*models.Task, error) {
+	return r.changeTaskTag(ctx, taskID, name, func(tags []string) []string {
+		if slices.Contains(tags, name) {
+			return nil
+		}
+
+		return append(slices.Clone(tags), name)
+	})
+}
+
+func (r *MemoryTaskRepository) DetachTag(ctx context.Context, taskID int, name string) (*models.Task, error) {
+	return r.changeTaskTag(ctx, taskID, name, func(tags []string) []string {
+		if !slices.Contains(tags, name) {
+			return nil
+		}
+
+		return without(tags, name)
+	})
+}
+
+// changeTaskTag заменяет метки задачи результатом change. Если change возвращает nil, задача не изменяется
+func (r *MemoryTaskRepository) changeTaskTag(ctx context.Context, taskID int, name string, change func([]string) []string) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing memory query: change task tag", "task_id", taskID, "tag", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tagByName(name); !ok {
+		slog.Warn("tag not found", "tag", name)
+		return nil, ErrTagNotFound
+	}
+
+	t, ok := r.activeTask(taskID)
+	if !ok {
+		slog.Warn("task not found for tag change", "task_id", taskID)
+		return nil, ErrNotFound
+	}
+
+	if tags := change(t.Tags); tags != nil {
+		before := t
+		now := time.Now().UTC()
+
+		sort.Strings(tags)
+		t.Tags = tags
+		t.UpdatedAt = now
+		t.Version++
+		r.tasks[taskID] = t
+		r.recordAudit(ctx, &before, &t, now)
+	}
+
+	return &t, nil
+}
+
+// retagTasks заменяет метки всех задач с меткой name результатом change, увеличивает их версию
+// и записывает изменение меток в журнал аудита. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) retagTasks(ctx context.Context, name string, change func([]string) []string) {
+	now := time.Now().UTC()
+
+	var before, after []models.Task
+	for id, t := range r.tasks {
+		if !slices.Contains(t.Tags, name) {
+			continue
+		}
+
+		before = append(before, t)
+		tags := change(t.Tags)
+		sort.Strings(tags)
+		t.Tags = tags
+		t.Version++
+		r.tasks[id] = t
+		after = append(after, t)
+	}
+
+	r.recordAuditTasks(ctx, models.UpdateAuditAction, before, after, now)
+}
+
+// tagByName ищет метку по названию. Вызывающий должен удерживать r.mu
+func (r *MemoryTaskRepository) tagByName(name string) (models.Tag, bool) {
+	for _, t := range r.tags {
+		if t.Name == name {
+			return t, true
+		}
+	}
+
+	return models.Tag{}, false
+}
+
+// without возвращает копию tags без name
+func without(tags []string, name string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t != name {
+			out = append(out, t)
+		}
+	}
+
+	return out
+}
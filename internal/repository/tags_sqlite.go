@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
+)
+
+func (r *SQLiteTaskRepository) ListTags(ctx context.Context) ([]models.Tag, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: list tags")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, created_at FROM tags ORDER BY name`)
+	if err != nil {
+		slog.Error("sqlite query failed: list tags", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+			slog.Error("failed to scan tag row", "error", err)
+			return nil, err
+		}
+
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("sqlite query failed: list tags", "error", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *SQLiteTaskRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: create tag", "name", tag.Name)
+	}
+
+	now := time.Now().UTC()
+
+	res, err := r.db.ExecContext(ctx, `INSERT INTO tags (name, created_at) VALUES (?, ?)`, tag.Name, now)
+	if err != nil {
+		slog.Error("sqlite query failed: create tag", "error", err, "name", tag.Name)
+		return sqliteError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		slog.Error("failed to get id of created tag", "error", err, "name", tag.Name)
+		return err
+	}
+
+	tag.ID = int(id)
+	tag.CreatedAt = now
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) RenameTag(ctx context.Context, id int, name string) (*models.Tag, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: rename tag", "id", id, "name", name)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: rename tag", "error", err, "tag_id", id)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tagged, err := sqliteTaggedTasks(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE tags SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		slog.Error("sqlite query failed: rename tag", "error", err, "tag_id", id)
+		return nil, sqliteError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: rename tag", "error", err, "tag_id", id)
+		return nil, err
+	}
+
+	if n == 0 {
+		slog.Warn("tag not found for rename", "tag_id", id)
+		return nil, ErrNotFound
+	}
+
+	if err := sqliteTouchTaggedTasks(ctx, tx, tagged); err != nil {
+		return nil, err
+	}
+
+	t := &models.Tag{}
+	query := `SELECT id, name, created_at FROM tags WHERE id = ?`
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+		slog.Error("sqlite query failed: rename tag", "error", err, "tag_id", id)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: rename tag", "error", err, "tag_id", id)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *SQLiteTaskRepository) DeleteTag(ctx context.Context, id int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: delete tag", "id", id)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: delete tag", "error", err, "tag_id", id)
+		return err
+	}
+	defer tx.Rollback()
+
+	tagged, err := sqliteTaggedTasks(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, id)
+	if err != nil {
+		slog.Error("sqlite query failed: delete tag", "error", err, "tag_id", id)
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: delete tag", "error", err, "tag_id", id)
+		return err
+	}
+
+	if n == 0 {
+		slog.Warn("tag not found for deletion", "tag_id", id)
+		return ErrNotFound
+	}
+
+	if err := sqliteTouchTaggedTasks(ctx, tx, tagged); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: delete tag", "error", err, "tag_id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) AttachTag(ctx context.Context, taskID int, name string) (*models.Task, error) {
+	return r.changeTaskTag(ctx, taskID, name, `INSERT INTO task_tags (task_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING`)
+}
+
+func (r *SQLiteTaskRepository) DetachTag(ctx context.Context, taskID int, name string) (*models.Task, error) {
+	return r.changeTaskTag(ctx, taskID, name, `DELETE FROM task_tags WHERE task_id = ? AND tag_id = ?`)
+}
+
+// changeTaskTag выполняет запрос change над связью задачи и метки, см. TaskRepository.changeTaskTag
+func (r *SQLiteTaskRepository) changeTaskTag(ctx context.Context, taskID int, name, change string) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing sqlite query: change task tag", "task_id", taskID, "tag", name)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("failed to begin sqlite transaction: change task tag", "error", err, "task_id", taskID)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var tagID int
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM tags WHERE name = ?`, name).Scan(&tagID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("tag not found", "tag", name)
+			return nil, ErrTagNotFound
+		}
+
+		slog.Error("sqlite query failed: change task tag", "error", err, "tag", name)
+
+		return nil, err
+	}
+
+	before, err := sqliteGetTask(ctx, tx, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("task not found for tag change", "task_id", taskID)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("sqlite query failed: change task tag", "error", err, "task_id", taskID)
+
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, change, taskID, tagID)
+	if err != nil {
+		slog.Error("sqlite query failed: change task tag", "error", err, "task_id", taskID, "tag", name)
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		slog.Error("sqlite query failed: change task tag", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	if n > 0 {
+		query := `UPDATE tasks SET updated_at = ?, version = version + 1 WHERE id = ?`
+		if _, err := tx.ExecContext(ctx, query, now, taskID); err != nil {
+			slog.Error("sqlite query failed: change task tag", "error", err, "task_id", taskID)
+			return nil, err
+		}
+	}
+
+	t, err := sqliteGetTask(ctx, tx, taskID)
+	if err != nil {
+		slog.Error("sqlite query failed: change task tag", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	if err := sqliteAudit(ctx, tx, taskID, before, t, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("failed to commit sqlite transaction: change task tag", "error", err, "task_id", taskID)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// sqliteTaggedTasks возвращает задачи с меткой tagID, в том числе из корзины
+func sqliteTaggedTasks(ctx context.Context, q sqliteQuerier, tagID int) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = ?) ORDER BY id`
+
+	tasks, err := sqliteQueryTasks(ctx, q, query, tagID)
+	if err != nil {
+		slog.Error("sqlite query failed: get tagged tasks", "error", err, "tag_id", tagID)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// sqliteTouchTaggedTasks увеличивает версию задач tagged после изменения их метки, см. pgTouchTaggedTasks
+func sqliteTouchTaggedTasks(ctx context.Context, q sqliteQuerier, tagged []models.Task) error {
+	if len(tagged) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(tagged))
+	args := make([]any, len(tagged))
+	for i, t := range tagged {
+		ids[i], args[i] = t.ID, t.ID
+	}
+
+	query := `UPDATE tasks SET version = version + 1 WHERE id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+	if _, err := q.ExecContext(ctx, query, args...); err != nil {
+		slog.Error("sqlite query failed: touch tagged tasks", "error", err, "task_ids", ids)
+		return err
+	}
+
+	after, err := sqliteGetTasks(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+
+	return sqliteAuditTasks(ctx, q, models.UpdateAuditAction, tagged, after, time.Now().UTC())
+}
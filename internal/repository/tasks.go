@@ -1,16 +1,26 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/NERFTHISPLS/rest-todo-list/internal/models"
-	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// pgQuerier общий интерфейс *pgxpool.Pool и pgx.Tx
+type pgQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
 type TaskRepository struct {
 	dbPool *pgxpool.Pool
 }
@@ -19,18 +29,20 @@ func NewTaskRepository(dbPool *pgxpool.Pool) *TaskRepository {
 	return &TaskRepository{dbPool: dbPool}
 }
 
-func (r *TaskRepository) List(c *fiber.Ctx) ([]models.Task, error) {
-	ctx := c.Context()
-
+func (r *TaskRepository) List(ctx context.Context, params ListParams) (*ListResult, error) {
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("executing database query: list tasks")
+		slog.Debug("executing database query: list tasks", "limit", params.Limit, "offset", params.Offset)
 	}
 
-	query := `
-		SELECT id, title, COALESCE(description, ''), status, created_at, updated_at
-		FROM tasks`
+	query, args, countQuery, countArgs := buildListQueries(params, pgDialect)
+
+	var total int
+	if err := r.dbPool.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		slog.Error("database query failed: count tasks", "error", err)
+		return nil, err
+	}
 
-	rows, err := r.dbPool.Query(ctx, query)
+	rows, err := r.dbPool.Query(ctx, query, args...)
 	if err != nil {
 		slog.Error("database query failed: list tasks", "error", err)
 		return nil, err
@@ -41,7 +53,7 @@ func (r *TaskRepository) List(c *fiber.Ctx) ([]models.Task, error) {
 
 	for rows.Next() {
 		var t models.Task
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		if err := scanTask(rows, &t); err != nil {
 			slog.Error("failed to scan task row", "error", err)
 
 			return nil, err
@@ -50,16 +62,463 @@ func (r *TaskRepository) List(c *fiber.Ctx) ([]models.Task, error) {
 		tasks = append(tasks, t)
 	}
 
+	if err := rows.Err(); err != nil {
+		slog.Error("database query failed: list tasks", "error", err)
+		return nil, err
+	}
+
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("database query completed: list tasks", "count", len(tasks))
+		slog.Debug("database query completed: list tasks", "count", len(tasks), "total", total)
 	}
 
-	return tasks, nil
+	return paginate(tasks, params, total), nil
+}
+
+func (r *TaskRepository) Get(ctx context.Context, id int) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: get task", "id", id)
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL`
+
+	t := &models.Task{}
+	if err := scanTask(r.dbPool.QueryRow(ctx, query, id), t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("task not found", "task_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: get task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: get task", "id", id)
+	}
+
+	return t, nil
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: create task", "error", err, "title", task.Title)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := pgCreateTask(ctx, tx, task); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: create task", "error", err, "task_id", task.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) Update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: update task", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	t, err := pgUpdateTask(ctx, tx, id, updates, version, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: update task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id int, version int) error {
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: delete task", "error", err, "task_id", id)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := pgDeleteTask(ctx, tx, id, version); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: delete task", "error", err, "task_id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) Modify(ctx context.Context, id int, modify func(t *models.Task) error, version int, opts UpdateOptions) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: modify task", "id", id, "version", version)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: modify task", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	t := &models.Task{}
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := scanTask(tx.QueryRow(ctx, query, id), t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("task not found for modification", "task_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: modify task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	if version > 0 && t.Version != version {
+		slog.Warn("task version mismatch for modification", "task_id", id, "version", version, "current", t.Version)
+		return nil, ErrVersionMismatch
+	}
+
+	status, before := t.Status, *t
+
+	if err := modify(t); err != nil {
+		return nil, err
+	}
+
+	if t.ProjectID != nil {
+		if err := pgCheckProject(ctx, tx, *t.ProjectID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.ParentID != nil {
+		if err := pgCheckParent(ctx, tx, *t.ParentID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	change, err := pgStatusChange(ctx, tx, status, t.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// completed подзадачи, выполненные вместе с задачей
+	completed := []int{}
+	if change.done {
+		if !opts.IgnoreBlockers {
+			if err := pgCheckOpenBlockers(ctx, tx, id); err != nil {
+				return nil, err
+			}
+		}
+
+		if completed, err = pgCompleteSubtasks(ctx, tx, id, t.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	query = `
+		UPDATE tasks
+		SET title = $1, description = NULLIF($2, ''), status = $3, project_id = $4, parent_id = $5, priority = $6,
+			due_at = $7, recurrence = $8, updated_at = now(), version = version + 1, ` + statusTimesSet("$3", "now()") + `
+		WHERE id = $9
+		RETURNING ` + taskColumns
+
+	row := tx.QueryRow(ctx, query, t.Title, t.Description, t.Status, t.ProjectID, t.ParentID, t.Priority, t.DueAt, t.Recurrence, id)
+	if err := scanTask(row, t); err != nil {
+		slog.Error("database query failed: modify task", "error", err, "task_id", id)
+		return nil, pgError(err)
+	}
+
+	if status != t.Status {
+		if err := pgRecordStatus(ctx, tx, id, status, t.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pgAudit(ctx, tx, id, &before, t); err != nil {
+		return nil, err
+	}
+
+	if relatedChanged(&before, t) {
+		if err := pgTouchRelated(ctx, tx, append([]int{id}, completed...), previousParent(&before, t)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if change.completing() {
+		if err := pgSpawnOccurrence(ctx, tx, t); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: modify task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: modify task", "id", id, "version", t.Version)
+	}
+
+	return t, nil
+}
+
+func (r *TaskRepository) Restore(ctx context.Context, id int) (*models.Task, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: restore task", "id", id)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: restore task", "error", err, "task_id", id)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT EXISTS (SELECT 1 FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NOT NULL)
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		FOR UPDATE`
+
+	var parentDeleted bool
+	if err := tx.QueryRow(ctx, query, id).Scan(&parentDeleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("task not found in trash", "task_id", id)
+			return nil, ErrNotFound
+		}
+
+		slog.Error("database query failed: restore task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	if parentDeleted {
+		slog.Warn("parent task is deleted", "task_id", id)
+		return nil, ErrParentDeleted
+	}
+
+	// вместе с задачей восстанавливаются потомки, удаленные одновременно с ней
+	query = subtreeCTE("id = $1", "child.deleted_at = (SELECT deleted_at FROM tasks root WHERE root.id = $1)") + `
+		UPDATE tasks SET deleted_at = NULL, updated_at = now(), version = version + 1
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING id`
+
+	ids, err := pgQueryIDs(ctx, tx, query, id)
+	if err != nil {
+		slog.Error("database query failed: restore task", "error", err, "task_id", id)
+		return nil, pgError(err)
+	}
+
+	if err := pgTouchRelated(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+
+	restored, err := pgGetTasks(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pgAuditTasks(ctx, tx, models.RestoreAuditAction, nil, restored); err != nil {
+		return nil, err
+	}
+
+	t := &models.Task{}
+	if err := scanTask(tx.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1`, id), t); err != nil {
+		slog.Error("database query failed: restore task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: restore task", "error", err, "task_id", id)
+		return nil, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: restore task", "id", id)
+	}
+
+	return t, nil
+}
+
+func (r *TaskRepository) Purge(ctx context.Context, id int) error {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: purge task", "id", id)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: purge task", "error", err, "task_id", id)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	n, err := pgPurgeTasks(ctx, tx, `id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		slog.Warn("task not found in trash", "task_id", id)
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: purge task", "error", err, "task_id", id)
+		return err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: purge task", "id", id)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: purge deleted tasks", "retention", retention)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: purge deleted tasks", "error", err)
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	n, err := pgPurgeTasks(ctx, tx, `deleted_at < now() - make_interval(secs => $1)`, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: purge deleted tasks", "error", err)
+		return 0, err
+	}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: purge deleted tasks", "rows_affected", n)
+	}
+
+	return n, nil
+}
+
+// pgPurgeTasks безвозвратно удаляет задачи, выбранные условием where, и записывает в журнал аудита их удаление
+// вместе с подзадачами, которые удаляет каскад. Возвращает количество задач, выбранных условием
+func pgPurgeTasks(ctx context.Context, q pgQuerier, where string, args ...any) (int, error) {
+	purged, err := pgQueryTasks(ctx, q, subtreeCTE(where, "true")+`
+		SELECT `+taskColumns+` FROM tasks WHERE id IN (SELECT id FROM subtree) ORDER BY id`, args...)
+	if err != nil {
+		slog.Error("database query failed: get purged tasks", "error", err)
+		return 0, err
+	}
+
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	cmd, err := q.Exec(ctx, `DELETE FROM tasks WHERE `+where, args...)
+	if err != nil {
+		slog.Error("database query failed: purge tasks", "error", err)
+		return 0, err
+	}
+
+	if err := pgAuditTasks(ctx, q, models.PurgeAuditAction, purged, nil); err != nil {
+		return 0, err
+	}
+
+	return int(cmd.RowsAffected()), nil
 }
 
-func (r *TaskRepository) Create(c *fiber.Ctx, task *models.Task) error {
-	ctx := c.Context()
+func (r *TaskRepository) Batch(ctx context.Context, ops []BatchOp, atomic bool) ([]BatchResult, error) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("executing database query: batch", "operations", len(ops), "atomic", atomic)
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction: batch", "error", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	results := make([]BatchResult, len(ops))
+
+	for i, op := range ops {
+		if atomic {
+			results[i] = applyBatchOp(ctx, pgTaskWriter{tx}, op)
+			if results[i].Err != nil {
+				slog.Warn("batch aborted", "index", i, "error", results[i].Err)
+				abortBatch(results, i)
+				return results, nil
+			}
+
+			continue
+		}
+
+		// в режиме best-effort каждая операция выполняется в своей точке сохранения,
+		// чтобы ошибка не прерывала всю транзакцию
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			slog.Error("failed to create savepoint: batch", "error", err, "index", i)
+			return nil, err
+		}
+
+		results[i] = applyBatchOp(ctx, pgTaskWriter{sp}, op)
+		if results[i].Err != nil {
+			err = sp.Rollback(ctx)
+		} else {
+			err = sp.Commit(ctx)
+		}
+		if err != nil {
+			slog.Error("failed to release savepoint: batch", "error", err, "index", i)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction: batch", "error", err)
+		return nil, err
+	}
 
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: batch", "operations", len(ops))
+	}
+
+	return results, nil
+}
+
+// pgTaskWriter выполняет операции пакета в транзакции PostgreSQL
+type pgTaskWriter struct {
+	q pgQuerier
+}
+
+func (w pgTaskWriter) create(ctx context.Context, task *models.Task) error {
+	return pgCreateTask(ctx, w.q, task)
+}
+
+func (w pgTaskWriter) update(ctx context.Context, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
+	return pgUpdateTask(ctx, w.q, id, updates, version, opts)
+}
+
+func (w pgTaskWriter) delete(ctx context.Context, id int, version int) error {
+	return pgDeleteTask(ctx, w.q, id, version)
+}
+
+func pgCreateTask(ctx context.Context, q pgQuerier, task *models.Task) error {
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
 		slog.Debug("executing database query: create task", "title", task.Title, "status", task.Status)
 	}
@@ -67,43 +526,151 @@ func (r *TaskRepository) Create(c *fiber.Ctx, task *models.Task) error {
 	if task.Status == "" {
 		task.Status = models.DefaultTaskStatus
 	}
+	if task.Priority == "" {
+		task.Priority = models.DefaultTaskPriority
+	}
+
+	if task.ProjectID != nil {
+		if err := pgCheckProject(ctx, q, *task.ProjectID, 0); err != nil {
+			return err
+		}
+	}
+
+	if task.ParentID != nil {
+		if err := pgCheckParent(ctx, q, *task.ParentID, 0); err != nil {
+			return err
+		}
+	}
+
+	if _, err := pgStatusChange(ctx, q, "", task.Status); err != nil {
+		return err
+	}
 
+	if err := pgInsertTask(ctx, q, task); err != nil {
+		return err
+	}
+
+	task.Tags = []string{}
+
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("database query completed: create task", "id", task.ID)
+	}
+
+	return nil
+}
+
+// pgInsertTask вставляет задачу без проверок, заполняет ID, даты и версию и записывает создание задачи
+// в историю статусов и журнал аудита
+func pgInsertTask(ctx context.Context, q pgQuerier, task *models.Task) error {
 	query := `
-		INSERT INTO tasks (title, description, status)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at
+		INSERT INTO tasks (
+			title, description, status, project_id, parent_id, priority, due_at, recurrence, series_id, spawned_from,
+			started_at, completed_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, ` + statusTimesValues("$3", "now()") + `)
+		RETURNING id, created_at, updated_at, started_at, completed_at, version
 	`
 
-	err := r.dbPool.QueryRow(
+	err := q.QueryRow(
 		ctx,
 		query,
 		task.Title,
 		task.Description,
 		task.Status,
-	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
+		task.ProjectID,
+		task.ParentID,
+		task.Priority,
+		task.DueAt,
+		task.Recurrence,
+		task.SeriesID,
+		task.SpawnedFrom,
+	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt, &task.StartedAt, &task.CompletedAt, &task.Version)
 
 	if err != nil {
 		slog.Error("database query failed: create task", "error", err, "title", task.Title)
+		return pgError(err)
+	}
+
+	if err := pgRecordStatus(ctx, q, task.ID, "", task.Status); err != nil {
 		return err
 	}
 
-	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("database query completed: create task", "id", task.ID)
+	if task.ParentID != nil {
+		if err := pgTouchRelated(ctx, q, []int{task.ID}); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return pgAudit(ctx, q, task.ID, nil, task)
 }
 
-func (r *TaskRepository) Update(c *fiber.Ctx, id int, updates map[string]any) (*models.Task, error) {
-	ctx := c.Context()
+// updatableColumns колонки задачи, которые можно изменить через Update. Ключи updates подставляются в SQL
+// как имена колонок, поэтому хранилища отклоняют остальные ключи
+var updatableColumns = map[string]bool{
+	"title": true, "description": true, "status": true, "project_id": true, "parent_id": true, "priority": true,
+	"due_at": true, "recurrence": true,
+}
 
+func pgUpdateTask(ctx context.Context, q pgQuerier, id int, updates map[string]any, version int, opts UpdateOptions) (*models.Task, error) {
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("executing database query: update task", "id", id, "updates", updates)
+		slog.Debug("executing database query: update task", "id", id, "updates", updates, "version", version)
 	}
 
 	if len(updates) == 0 {
 		slog.Warn("no fields to update", "task_id", id)
-		return nil, fmt.Errorf("no fields to update")
+		return nil, fmt.Errorf("%w: no fields to update", ErrInvalid)
+	}
+
+	for k := range updates {
+		if !updatableColumns[k] {
+			slog.Warn("unknown field to update", "task_id", id, "field", k)
+			return nil, fmt.Errorf("%w: unknown field %s", ErrInvalid, k)
+		}
+	}
+
+	if projectID, ok := updatedProjectID(updates); ok {
+		if err := pgCheckProject(ctx, q, projectID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if parentID, ok := updatedParentID(updates); ok {
+		if err := pgCheckParent(ctx, q, parentID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	// before задача до изменения для журнала аудита, nil если задачи нет
+	before, err := pgLockTask(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// current прежний статус задачи, если updates его меняют, completed - подзадачи, выполненные вместе с ней
+	current, completing, completed := "", false, []int{}
+	if status, ok := updates["status"].(string); ok {
+		if before != nil {
+			current = before.Status
+		}
+
+		change, err := pgStatusChange(ctx, q, current, status)
+		if err != nil {
+			return nil, err
+		}
+
+		if change.done {
+			if !opts.IgnoreBlockers {
+				if err := pgCheckOpenBlockers(ctx, q, id); err != nil {
+					return nil, err
+				}
+			}
+
+			if completed, err = pgCompleteSubtasks(ctx, q, id, status); err != nil {
+				return nil, err
+			}
+		}
+
+		completing = change.completing()
 	}
 
 	setClauses := []string{}
@@ -114,31 +681,64 @@ func (r *TaskRepository) Update(c *fiber.Ctx, id int, updates map[string]any) (*
 		args = append(args, v)
 		i++
 	}
-	setClauses = append(setClauses, "updated_at = now()")
+	setClauses = append(setClauses, "updated_at = now()", "version = version + 1")
+
+	if status, ok := updates["status"].(string); ok {
+		setClauses = append(setClauses, statusTimesSet(fmt.Sprintf("$%d", i), "now()"))
+		args = append(args, status)
+		i++
+	}
+
+	where := fmt.Sprintf("id = $%d AND deleted_at IS NULL", i)
+	args = append(args, id)
+	if version > 0 {
+		where += fmt.Sprintf(" AND version = $%d", i+1)
+		args = append(args, version)
+	}
 
 	query := fmt.Sprintf(`
 		UPDATE tasks
 		SET %s
-		WHERE id = $%d
-		RETURNING id, title, description, status, created_at, updated_at
-	`, strings.Join(setClauses, ", "), i)
-
-	args = append(args, id)
+		WHERE %s
+		RETURNING %s
+	`, strings.Join(setClauses, ", "), where, taskColumns)
 
-	row := r.dbPool.QueryRow(ctx, query, args...)
+	row := q.QueryRow(ctx, query, args...)
 
 	t := &models.Task{}
-	if err := row.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.CreatedAt, &t.UpdatedAt); err != nil {
-		if errors.Is(err, fiber.ErrNotFound) {
-			slog.Warn("task not found for update", "task_id", id)
-			return nil, fiber.ErrNotFound
+	if err := scanTask(row, t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("task not updated: not found or version mismatch", "task_id", id, "version", version)
+			return nil, pgMissingTaskError(ctx, q, id, version)
 		}
 
 		slog.Error("database query failed: update task", "error", err, "task_id", id)
 
+		return nil, pgError(err)
+	}
+
+	if current != "" && current != t.Status {
+		if err := pgRecordStatus(ctx, q, id, current, t.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pgAudit(ctx, q, id, before, t); err != nil {
 		return nil, err
 	}
 
+	if relatedChanged(before, t) {
+		if err := pgTouchRelated(ctx, q, append([]int{id}, completed...), previousParent(before, t)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if completing {
+		if err := pgSpawnOccurrence(ctx, q, t); err != nil {
+			return nil, err
+		}
+	}
+
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
 		slog.Debug("database query completed: update task", "id", id)
 	}
@@ -146,27 +746,145 @@ func (r *TaskRepository) Update(c *fiber.Ctx, id int, updates map[string]any) (*
 	return t, nil
 }
 
-func (r *TaskRepository) Delete(c *fiber.Ctx, id int) error {
-	ctx := c.Context()
-
+func pgDeleteTask(ctx context.Context, q pgQuerier, id int, version int) error {
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("executing database query: delete task", "id", id)
+		slog.Debug("executing database query: delete task", "id", id, "version", version)
+	}
+
+	// задача перемещается в корзину вместе со всеми активными потомками
+	root := `id = $1 AND deleted_at IS NULL`
+	args := []any{id}
+	if version > 0 {
+		root += ` AND version = $2`
+		args = append(args, version)
 	}
 
-	query := `DELETE FROM tasks WHERE id = $1`
-	cmd, err := r.dbPool.Exec(ctx, query, id)
+	query := subtreeCTE(root, "child.deleted_at IS NULL") + `
+		UPDATE tasks SET deleted_at = now(), updated_at = now(), version = version + 1
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING id`
+
+	ids, err := pgQueryIDs(ctx, q, query, args...)
 	if err != nil {
 		slog.Error("database query failed: delete task", "error", err, "task_id", id)
+		return pgError(err)
+	}
+
+	if len(ids) == 0 {
+		slog.Warn("no rows affected when deleting task", "task_id", id, "version", version)
+		return pgMissingTaskError(ctx, q, id, version)
+	}
+
+	if err := pgTouchRelated(ctx, q, ids); err != nil {
 		return err
 	}
 
-	if cmd.RowsAffected() == 0 {
-		slog.Warn("no rows affected when deleting task", "task_id", id)
-		return fiber.ErrNotFound
+	deleted, err := pgGetTasks(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+
+	if err := pgAuditTasks(ctx, q, models.DeleteAuditAction, deleted, nil); err != nil {
+		return err
 	}
 
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("database query completed: delete task", "id", id, "rows_affected", cmd.RowsAffected())
+		slog.Debug("database query completed: delete task", "id", id, "rows_affected", len(ids))
+	}
+
+	return nil
+}
+
+// pgMissingTaskError определяет, почему условное изменение задачи не затронуло ни одной строки:
+// задачи нет или ее версия отличается от ожидаемой
+func pgMissingTaskError(ctx context.Context, q pgQuerier, id int, version int) error {
+	if version == 0 {
+		return ErrNotFound
+	}
+
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		slog.Error("database query failed: check task existence", "error", err, "task_id", id)
+		return err
+	}
+
+	if exists {
+		return ErrVersionMismatch
+	}
+
+	return ErrNotFound
+}
+
+// pgLockTask блокирует активную задачу id до конца транзакции и возвращает ее или nil, если задачи нет
+func pgLockTask(ctx context.Context, q pgQuerier, id int) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	t := &models.Task{}
+	if err := scanTask(q.QueryRow(ctx, query, id), t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		slog.Error("database query failed: lock task", "error", err, "task_id", id)
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// pgQueryTasks выполняет запрос, возвращающий колонки taskColumns. Строки читаются полностью,
+// поэтому по результату можно выполнять следующие запросы в той же транзакции
+func pgQueryTasks(ctx context.Context, q pgQuerier, query string, args ...any) ([]models.Task, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Task, error) {
+		var t models.Task
+		err := scanTask(row, &t)
+		return t, err
+	})
+}
+
+// pgGetTasks возвращает задачи ids, в том числе из корзины, по возрастанию ID
+func pgGetTasks(ctx context.Context, q pgQuerier, ids []int) ([]models.Task, error) {
+	tasks, err := pgQueryTasks(ctx, q, `SELECT `+taskColumns+` FROM tasks WHERE id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		slog.Error("database query failed: get tasks", "error", err, "task_ids", ids)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// pgQueryIDs выполняет запрос, возвращающий ID задач
+func pgQueryIDs(ctx context.Context, q pgQuerier, query string, args ...any) ([]int, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// pgTouchRelated увеличивает версию активных задач, в представление которых входят задачи ids:
+// родителей, прогресс подзадач которых зависит от ids, задач, связанных с ids блокировкой, и задач others.
+// Сами задачи ids не изменяются
+func pgTouchRelated(ctx context.Context, q pgQuerier, ids []int, others ...int) error {
+	query := `
+		UPDATE tasks SET updated_at = now(), version = version + 1
+		WHERE deleted_at IS NULL AND id <> ALL($1) AND (
+			id IN (SELECT parent_id FROM tasks WHERE id = ANY($1))
+			OR id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = ANY($1))
+			OR id IN (SELECT task_id FROM task_dependencies WHERE blocker_id = ANY($1))
+			OR id = ANY($2)
+		)`
+
+	if _, err := q.Exec(ctx, query, ids, append([]int{}, others...)); err != nil {
+		slog.Error("database query failed: touch related tasks", "error", err, "task_ids", ids)
+		return err
 	}
 
 	return nil
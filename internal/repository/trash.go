@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RunTrashPurge каждые interval безвозвратно удаляет задачи, которые находятся в корзине дольше retention.
+// Блокируется до отмены ctx, поэтому запускается в отдельной горутине
+func RunTrashPurge(ctx context.Context, store TaskStore, retention, interval time.Duration) {
+	slog.Info("trash purge started", "retention", retention, "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := store.PurgeDeleted(ctx, retention)
+		if err != nil {
+			slog.Error("failed to purge trash", "error", err)
+		} else if n > 0 {
+			slog.Info("trash purged", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("trash purge stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
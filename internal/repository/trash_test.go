@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// listIDs возвращает ID активных задач r или, при deleted, задач из корзины
+func listIDs(t *testing.T, r *MemoryTaskRepository, deleted bool) []int {
+	t.Helper()
+
+	res, err := r.List(context.Background(), ListParams{Filter: TaskFilter{Deleted: deleted}})
+	if err != nil {
+		t.Fatalf("List(deleted=%v) error = %v", deleted, err)
+	}
+
+	ids := []int{}
+	for _, task := range res.Tasks {
+		ids = append(ids, task.ID)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+func TestDeleteMovesToTrash(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	mustCreate(t, r, "a")
+	mustCreate(t, r, "b")
+
+	if err := r.Delete(ctx, 1, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if got := listIDs(t, r, false); !slices.Equal(got, []int{2}) {
+		t.Errorf("active tasks = %v, want [2]", got)
+	}
+
+	if got := listIDs(t, r, true); !slices.Equal(got, []int{1}) {
+		t.Errorf("trash = %v, want [1]", got)
+	}
+
+	if _, err := r.Get(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of deleted task error = %v, want %v", err, ErrNotFound)
+	}
+
+	if err := r.Delete(ctx, 1, 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	mustCreate(t, r, "a")
+
+	if _, err := r.Restore(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Restore() of active task error = %v, want %v", err, ErrNotFound)
+	}
+
+	if err := r.Delete(ctx, 1, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	deleted := r.tasks[1].Version
+
+	restored, err := r.Restore(ctx, 1)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if restored.DeletedAt != nil {
+		t.Errorf("restored DeletedAt = %v, want nil", restored.DeletedAt)
+	}
+
+	if restored.Version <= deleted {
+		t.Errorf("restored version = %d, want greater than %d", restored.Version, deleted)
+	}
+
+	if got := mustGet(t, r, 1); got.Version != restored.Version {
+		t.Errorf("Get() version = %d, want %d", got.Version, restored.Version)
+	}
+
+	if got := listIDs(t, r, false); !slices.Equal(got, []int{1}) {
+		t.Errorf("active tasks = %v, want [1]", got)
+	}
+
+	if got := listIDs(t, r, true); len(got) != 0 {
+		t.Errorf("trash = %v, want empty", got)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	tests := []struct {
+		name   string
+		delete bool
+		id     int
+		want   error
+	}{
+		{"task in trash", true, 1, nil},
+		{"active task", false, 1, ErrNotFound},
+		{"missing task", false, 99, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := NewMemoryTaskRepository()
+			mustCreate(t, r, "a")
+
+			if tt.delete {
+				if err := r.Delete(ctx, 1, 0); err != nil {
+					t.Fatalf("Delete() error = %v", err)
+				}
+			}
+
+			if err := r.Purge(ctx, tt.id); !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+				t.Fatalf("Purge(%d) error = %v, want %v", tt.id, err, tt.want)
+			}
+
+			if tt.want != nil {
+				if tt.id == 1 {
+					mustGet(t, r, 1)
+				}
+				return
+			}
+
+			if got := listIDs(t, r, true); len(got) != 0 {
+				t.Errorf("trash = %v, want empty", got)
+			}
+
+			if _, err := r.Restore(ctx, 1); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Restore() of purged task error = %v, want %v", err, ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+
+	// задачи 1-3 лежат в корзине 10 дней, 2 дня и 1 минуту, задача 4 активна
+	now := time.Now().UTC()
+	for i, age := range []time.Duration{240 * time.Hour, 48 * time.Hour, time.Minute} {
+		task := mustCreate(t, r, "a")
+		if err := r.Delete(ctx, task.ID, 0); err != nil {
+			t.Fatalf("Delete(%d) error = %v", task.ID, err)
+		}
+
+		deletedAt := now.Add(-age)
+		stored := r.tasks[i+1]
+		stored.DeletedAt = &deletedAt
+		r.tasks[i+1] = stored
+	}
+	mustCreate(t, r, "active")
+
+	tests := []struct {
+		retention time.Duration
+		purged    int
+		trash     []int
+	}{
+		{30 * 24 * time.Hour, 0, []int{1, 2, 3}},
+		{7 * 24 * time.Hour, 1, []int{2, 3}},
+		{24 * time.Hour, 1, []int{3}},
+		{time.Hour, 0, []int{3}},
+	}
+
+	for _, tt := range tests {
+		n, err := r.PurgeDeleted(ctx, tt.retention)
+		if err != nil {
+			t.Fatalf("PurgeDeleted(%v) error = %v", tt.retention, err)
+		}
+
+		if n != tt.purged {
+			t.Errorf("PurgeDeleted(%v) = %d, want %d", tt.retention, n, tt.purged)
+		}
+
+		if got := listIDs(t, r, true); !slices.Equal(got, tt.trash) {
+			t.Errorf("trash after PurgeDeleted(%v) = %v, want %v", tt.retention, got, tt.trash)
+		}
+	}
+
+	if got := listIDs(t, r, false); !slices.Equal(got, []int{4}) {
+		t.Errorf("active tasks = %v, want [4]", got)
+	}
+}
@@ -0,0 +1,40 @@
+// Package requestinfo передает хранилищу сведения об HTTP запросе, которые сохраняются вместе с изменениями задач
+package requestinfo
+
+import "context"
+
+// ActorHeader заголовок запроса с автором изменений. Аутентификации нет, поэтому автор указывается клиентом
+// и не проверяется: это подсказка, а не доказательство авторства. Проверить ее помогают IP адрес и идентификатор
+// запроса, которые сохраняются вместе с автором
+const ActorHeader = "X-Actor"
+
+// MaxActorLength максимальная длина автора изменений в символах
+const MaxActorLength = 100
+
+// Info сведения о запросе, пустые поля означают, что значение неизвестно
+type Info struct {
+	// Actor автор из заголовка X-Actor, указанный клиентом и не проверенный сервером
+	Actor string
+	// RequestID идентификатор запроса из заголовка X-Request-ID или сгенерированный сервером
+	RequestID string
+	// IP адрес клиента
+	IP string
+}
+
+type contextKey struct{}
+
+// userValueSetter контекст запроса, значения которого затем доступны через Value, например *fasthttp.RequestCtx
+type userValueSetter interface {
+	SetUserValue(key, value any)
+}
+
+// Set сохраняет сведения в контексте запроса, чтобы хранилище получило их из context.Context обработчика
+func Set(ctx userValueSetter, info Info) {
+	ctx.SetUserValue(contextKey{}, info)
+}
+
+// From возвращает сведения о запросе из ctx или пустые сведения, если изменение выполняется не по запросу
+func From(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+	return info
+}
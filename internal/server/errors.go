@@ -0,0 +1,29 @@
+package server
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorHandler отвечает на любую ошибку обработчика в формате application/problem+json.
+// Ошибки, не являющиеся problem.Problem или fiber.Error, скрываются за internal_error
+func errorHandler(c *fiber.Ctx, err error) error {
+	var p *problem.Problem
+	if !errors.As(err, &p) {
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			p = problem.New(fe.Code, problem.FromStatus(fe.Code), fe.Message)
+		} else {
+			slog.Error("unhandled request error", "error", err, "path", c.Path(), "ip", c.IP())
+			p = problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "internal server error")
+		}
+	}
+
+	resp := *p
+	resp.Instance = c.Path()
+
+	return c.Status(resp.Status).JSON(resp, problem.ContentType)
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/NERFTHISPLS/rest-todo-list/internal/problem"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/requestinfo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestInfo передает хранилищу сведения о запросе, см. requestinfo.Info. Идентификатор запроса
+// выставляет middleware requestid, поэтому requestInfo подключается после него
+func requestInfo(c *fiber.Ctx) error {
+	// значение заголовка ссылается на буфер запроса, а автор сохраняется в хранилище
+	actor := strings.Clone(strings.TrimSpace(c.Get(requestinfo.ActorHeader)))
+	if utf8.RuneCountInString(actor) > requestinfo.MaxActorLength {
+		detail := fmt.Sprintf("%s header must be at most %d characters", requestinfo.ActorHeader, requestinfo.MaxActorLength)
+		return problem.New(fiber.StatusBadRequest, problem.CodeInvalidRequest, detail)
+	}
+
+	requestinfo.Set(c.Context(), requestinfo.Info{
+		Actor:     actor,
+		RequestID: strings.Clone(c.GetRespHeader(fiber.HeaderXRequestID)),
+		IP:        strings.Clone(c.IP()),
+	})
+
+	return c.Next()
+}
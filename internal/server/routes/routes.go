@@ -2,19 +2,66 @@ package routes
 
 import (
 	_ "github.com/NERFTHISPLS/rest-todo-list/docs"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/config"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/audit"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/projects"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/statuses"
+	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/tags"
 	"github.com/NERFTHISPLS/rest-todo-list/internal/handlers/tasks"
 	"github.com/NERFTHISPLS/rest-todo-list/internal/repository"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
 )
 
-func Setup(app *fiber.App, repo *repository.TaskRepository) {
-	taskHandler := tasks.NewHandler(repo)
+func Setup(app *fiber.App, cfg *config.ConfServer, repo repository.Store) {
+	taskHandler := tasks.NewHandler(repo, cfg)
+	projectHandler := projects.NewHandler(repo)
+	tagHandler := tags.NewHandler(repo)
+	statusHandler := statuses.NewHandler(repo)
+	auditHandler := audit.NewHandler(repo, cfg)
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
 	app.Get("/tasks", taskHandler.List)
+	app.Get("/tasks/search", taskHandler.Search)
+	app.Get("/tasks/overdue", taskHandler.Overdue)
+	app.Get("/tasks/due", taskHandler.Due)
+	app.Get("/tasks/:id", taskHandler.Get)
 	app.Post("/tasks", taskHandler.Create)
+	app.Post("/tasks/batch", taskHandler.Batch)
 	app.Put("/tasks/:id", taskHandler.Update)
+	app.Patch("/tasks/:id", taskHandler.Patch)
 	app.Delete("/tasks/:id", taskHandler.Delete)
+	app.Post("/tasks/:id/restore", taskHandler.Restore)
+	app.Get("/tasks/:id/subtasks", taskHandler.Subtasks)
+	app.Get("/tasks/:id/tree", taskHandler.Tree)
+	app.Get("/tasks/:id/history", taskHandler.History)
+	app.Put("/tasks/:id/blockers/:blocker_id", taskHandler.AddBlocker)
+	app.Delete("/tasks/:id/blockers/:blocker_id", taskHandler.RemoveBlocker)
+	app.Put("/tasks/:id/tags/:name", taskHandler.AttachTag)
+	app.Delete("/tasks/:id/tags/:name", taskHandler.DetachTag)
+
+	app.Get("/trash", taskHandler.Trash)
+	app.Delete("/trash/:id", taskHandler.Purge)
+
+	app.Get("/projects", projectHandler.List)
+	app.Get("/projects/:id", projectHandler.Get)
+	app.Get("/projects/:id/tasks", taskHandler.ProjectTasks)
+	app.Post("/projects", projectHandler.Create)
+	app.Put("/projects/:id", projectHandler.Update)
+	app.Delete("/projects/:id", projectHandler.Delete)
+	app.Post("/projects/:id/archive", projectHandler.Archive)
+	app.Post("/projects/:id/unarchive", projectHandler.Unarchive)
+
+	app.Get("/tags", tagHandler.List)
+	app.Post("/tags", tagHandler.Create)
+	app.Put("/tags/:id", tagHandler.Rename)
+	app.Delete("/tags/:id", tagHandler.Delete)
+
+	app.Get("/statuses", statusHandler.List)
+	app.Post("/statuses", statusHandler.Create)
+	app.Put("/statuses/:name", statusHandler.Update)
+	app.Delete("/statuses/:name", statusHandler.Delete)
+
+	app.Get("/audit", auditHandler.List)
 }
@@ -11,15 +11,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 )
 
-func Setup(cfg *config.ConfServer, repo *repository.TaskRepository) error {
+func Setup(cfg *config.ConfServer, repo repository.Store) error {
 	slog.Info("starting server", "port", cfg.Port)
 
 	app := fiber.New(fiber.Config{
 		ReadTimeout:  cfg.TimeoutRead,
 		WriteTimeout: cfg.TimeoutWrite,
 		IdleTimeout:  cfg.TimeoutIdle,
+		ErrorHandler: errorHandler,
 	})
 
 	logFormat := "[${time}] ${status} - ${latency} ${method} ${path} - ${ip}\n"
@@ -41,13 +43,17 @@ func Setup(cfg *config.ConfServer, repo *repository.TaskRepository) error {
 	}))
 
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE",
+		AllowOrigins:  "*",
+		AllowMethods:  "GET,POST,PUT,PATCH,DELETE",
+		ExposeHeaders: "ETag,Link,X-Total-Count,X-Next-Cursor,X-Request-ID",
 	}))
 
+	app.Use(requestid.New())
+	app.Use(requestInfo)
+
 	serverPort := fmt.Sprintf(":%d", cfg.Port)
 
-	routes.Setup(app, repo)
+	routes.Setup(app, cfg, repo)
 
 	slog.Info("server configured successfully", "port", cfg.Port)
 
@@ -0,0 +1,162 @@
+// Package validation проверяет данные запросов и собирает ошибки по отдельным полям
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Коды ошибок полей
+const (
+	CodeRequired      = "required"
+	CodeTooLong       = "too_long"
+	CodeInvalidEnum   = "invalid_enum"
+	CodeInvalidType   = "invalid_type"
+	CodeUnknownField  = "unknown_field"
+	CodeOutOfRange    = "out_of_range"
+	CodeNotFound      = "not_found"
+	CodeInvalidFormat = "invalid_format"
+	CodeCycle         = "cycle"
+)
+
+// ErrInvalidJSON возвращается, когда тело запроса не является JSON объектом
+var ErrInvalidJSON = errors.New("request body must be a JSON object")
+
+// FieldError ошибка проверки отдельного поля
+type FieldError struct {
+	Field   string `json:"field" example:"title"`
+	Code    string `json:"code" example:"required"`
+	Message string `json:"message" example:"title is required"`
+}
+
+// Errors ошибки проверки всех полей запроса
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Message
+	}
+
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validator накапливает ошибки полей. Нулевое значение готово к использованию
+type Validator struct {
+	errs Errors
+}
+
+func (v *Validator) Add(field, code, msg string) {
+	v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: msg})
+}
+
+// Required проверяет, что строка не пустая и не состоит из пробелов
+func (v *Validator) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, field+" is required")
+	}
+}
+
+// MaxLength проверяет длину строки в символах
+func (v *Validator) MaxLength(field, value string, maxLength int) {
+	if utf8.RuneCountInString(value) > maxLength {
+		v.Add(field, CodeTooLong, fmt.Sprintf("%s must be at most %d characters", field, maxLength))
+	}
+}
+
+// OneOf проверяет, что значение входит в список допустимых
+func (v *Validator) OneOf(field, value string, allowed []string) {
+	if !slices.Contains(allowed, value) {
+		v.Add(field, CodeInvalidEnum, fmt.Sprintf("%s must be one of: %s", field, strings.Join(allowed, ", ")))
+	}
+}
+
+// Positive проверяет, что число больше нуля
+func (v *Validator) Positive(field string, value int) {
+	if value <= 0 {
+		v.Add(field, CodeOutOfRange, field+" must be a positive integer")
+	}
+}
+
+// Matches проверяет, что строка соответствует шаблону, описание которого rule попадает в сообщение
+func (v *Validator) Matches(field, value string, re *regexp.Regexp, rule string) {
+	if !re.MatchString(value) {
+		v.Add(field, CodeInvalidFormat, field+" must "+rule)
+	}
+}
+
+// Failed сообщает, есть ли уже ошибки поля field, например неверный тип значения
+func (v *Validator) Failed(field string) bool {
+	return slices.ContainsFunc(v.errs, func(fe FieldError) bool {
+		return fe.Field == field
+	})
+}
+
+// Err возвращает Errors, если были ошибки, иначе nil
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+// DecodeObject разбирает JSON объект, раскладывая известные поля по указателям fields.
+// Неизвестные поля и значения неверного типа добавляются в v как ошибки полей.
+// Возвращает множество переданных полей или ErrInvalidJSON, если body не JSON объект
+func (v *Validator) DecodeObject(body []byte, fields map[string]any) (map[string]bool, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil || raw == nil {
+		return nil, ErrInvalidJSON
+	}
+
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	present := map[string]bool{}
+
+	for _, k := range keys {
+		dst, ok := fields[k]
+		if !ok {
+			v.Add(k, CodeUnknownField, "unknown field "+k)
+			continue
+		}
+
+		present[k] = true
+
+		dec := json.NewDecoder(bytes.NewReader(raw[k]))
+		if err := dec.Decode(dst); err != nil {
+			v.Add(k, CodeInvalidType, fmt.Sprintf("%s must be %s", k, typeName(dst)))
+		}
+	}
+
+	return present, nil
+}
+
+func typeName(dst any) string {
+	switch dst.(type) {
+	case *string:
+		return "a string"
+	case **string:
+		return "a string or null"
+	case *int, **int:
+		return "an integer"
+	case *bool:
+		return "a boolean"
+	case *time.Time, **time.Time:
+		return "an RFC 3339 timestamp"
+	default:
+		return "a valid value"
+	}
+}
@@ -0,0 +1,268 @@
+package validation
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+// taskFields поля, которые разбирает DecodeObject в тестах
+type taskFields struct {
+	Title       string
+	Description *string
+	ProjectID   *int
+	Done        bool
+	DueAt       *time.Time
+}
+
+func (f *taskFields) fields() map[string]any {
+	return map[string]any{
+		"title":       &f.Title,
+		"description": &f.Description,
+		"project_id":  &f.ProjectID,
+		"done":        &f.Done,
+		"due_at":      &f.DueAt,
+	}
+}
+
+func codes(errs Errors) []string {
+	out := make([]string, len(errs))
+	for i, fe := range errs {
+		out[i] = fe.Field + ":" + fe.Code
+	}
+
+	return out
+}
+
+func TestDecodeObjectInvalidJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", ``},
+		{"not json", `title=a`},
+		{"null", `null`},
+		{"array", `[{"title":"a"}]`},
+		{"string", `"a"`},
+		{"truncated", `{"title":"a"`},
+		{"trailing object", `{"title":"a"} {"title":"b"}`},
+		{"trailing garbage", `{"title":"a"}x`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+			var f taskFields
+
+			if _, err := v.DecodeObject([]byte(tt.body), f.fields()); !errors.Is(err, ErrInvalidJSON) {
+				t.Errorf("DecodeObject() error = %v, want %v", err, ErrInvalidJSON)
+			}
+			if err := v.Err(); err != nil {
+				t.Errorf("Err() = %v, want no field errors", err)
+			}
+		})
+	}
+}
+
+func TestDecodeObject(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		present []string
+		errs    []string
+		check   func(t *testing.T, f *taskFields)
+	}{
+		{
+			name:    "all fields",
+			body:    `{"title":"a","description":"d","project_id":2,"done":true,"due_at":"2025-08-20T18:00:00Z"}`,
+			present: []string{"description", "done", "due_at", "project_id", "title"},
+			check: func(t *testing.T, f *taskFields) {
+				if f.Title != "a" || f.Description == nil || *f.Description != "d" || f.ProjectID == nil || *f.ProjectID != 2 ||
+					!f.Done || f.DueAt == nil || !f.DueAt.Equal(time.Date(2025, time.August, 20, 18, 0, 0, 0, time.UTC)) {
+					t.Errorf("decoded %+v", f)
+				}
+			},
+		},
+		{
+			name:    "absent fields",
+			body:    `{}`,
+			present: []string{},
+			check: func(t *testing.T, f *taskFields) {
+				if f.Description == nil || f.ProjectID == nil {
+					t.Errorf("absent fields were overwritten: %+v", f)
+				}
+			},
+		},
+		{
+			name:    "explicit null",
+			body:    `{"description":null,"project_id":null}`,
+			present: []string{"description", "project_id"},
+			check: func(t *testing.T, f *taskFields) {
+				if f.Description != nil || f.ProjectID != nil {
+					t.Errorf("null fields were not cleared: %+v", f)
+				}
+			},
+		},
+		{
+			name:    "unknown fields",
+			body:    `{"zeta":1,"title":"a","alpha":2}`,
+			present: []string{"title"},
+			errs:    []string{"alpha:" + CodeUnknownField, "zeta:" + CodeUnknownField},
+		},
+		{
+			name:    "wrong types",
+			body:    `{"title":1,"description":false,"project_id":"2","done":"yes","due_at":"tomorrow"}`,
+			present: []string{"description", "done", "due_at", "project_id", "title"},
+			errs: []string{
+				"description:" + CodeInvalidType, "done:" + CodeInvalidType, "due_at:" + CodeInvalidType,
+				"project_id:" + CodeInvalidType, "title:" + CodeInvalidType,
+			},
+		},
+		{
+			name:    "fractional integer",
+			body:    `{"project_id":1.5}`,
+			present: []string{"project_id"},
+			errs:    []string{"project_id:" + CodeInvalidType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+
+			description, projectID := "old", 1
+			f := &taskFields{Description: &description, ProjectID: &projectID}
+
+			present, err := v.DecodeObject([]byte(tt.body), f.fields())
+			if err != nil {
+				t.Fatalf("DecodeObject() error = %v", err)
+			}
+
+			got := []string{}
+			for k := range present {
+				got = append(got, k)
+			}
+			slices.Sort(got)
+
+			if !slices.Equal(got, tt.present) {
+				t.Errorf("present = %v, want %v", got, tt.present)
+			}
+
+			var errs Errors
+			if err := v.Err(); err != nil && !errors.As(err, &errs) {
+				t.Fatalf("Err() = %T, want Errors", err)
+			}
+			if got := codes(errs); !slices.Equal(got, tt.errs) {
+				t.Errorf("errors = %v, want %v", got, tt.errs)
+			}
+
+			if tt.check != nil {
+				tt.check(t, f)
+			}
+		})
+	}
+}
+
+func TestDecodeObjectTypeMessages(t *testing.T) {
+	var v Validator
+	var f taskFields
+
+	body := `{"title":1,"description":1,"project_id":"x","done":1,"due_at":1}`
+	if _, err := v.DecodeObject([]byte(body), f.fields()); err != nil {
+		t.Fatalf("DecodeObject() error = %v", err)
+	}
+
+	want := map[string]string{
+		"title":       "title must be a string",
+		"description": "description must be a string or null",
+		"project_id":  "project_id must be an integer",
+		"done":        "done must be a boolean",
+		"due_at":      "due_at must be an RFC 3339 timestamp",
+	}
+
+	var errs Errors
+	if !errors.As(v.Err(), &errs) {
+		t.Fatalf("Err() = %v, want Errors", v.Err())
+	}
+
+	for _, fe := range errs {
+		if fe.Message != want[fe.Field] {
+			t.Errorf("%s message = %q, want %q", fe.Field, fe.Message, want[fe.Field])
+		}
+	}
+}
+
+func TestValidatorRules(t *testing.T) {
+	re := regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+	tests := []struct {
+		name  string
+		check func(v *Validator)
+		want  []string
+	}{
+		{"required", func(v *Validator) { v.Required("title", "a") }, nil},
+		{"required empty", func(v *Validator) { v.Required("title", "") }, []string{"title:" + CodeRequired}},
+		{"required spaces", func(v *Validator) { v.Required("title", " \t\n") }, []string{"title:" + CodeRequired}},
+		{"max length", func(v *Validator) { v.MaxLength("title", "abc", 3) }, nil},
+		{"max length in runes", func(v *Validator) { v.MaxLength("title", "ябл", 3) }, nil},
+		{"too long", func(v *Validator) { v.MaxLength("title", "abcd", 3) }, []string{"title:" + CodeTooLong}},
+		{"too long in runes", func(v *Validator) { v.MaxLength("title", "яблоко", 5) }, []string{"title:" + CodeTooLong}},
+		{"enum", func(v *Validator) { v.OneOf("priority", "high", []string{"low", "high"}) }, nil},
+		{"enum mismatch", func(v *Validator) { v.OneOf("priority", "High", []string{"low", "high"}) }, []string{"priority:" + CodeInvalidEnum}},
+		{"enum empty", func(v *Validator) { v.OneOf("priority", "", []string{"low", "high"}) }, []string{"priority:" + CodeInvalidEnum}},
+		{"positive", func(v *Validator) { v.Positive("project_id", 1) }, nil},
+		{"zero", func(v *Validator) { v.Positive("project_id", 0) }, []string{"project_id:" + CodeOutOfRange}},
+		{"negative", func(v *Validator) { v.Positive("project_id", -1) }, []string{"project_id:" + CodeOutOfRange}},
+		{"matches", func(v *Validator) { v.Matches("name", "in_review", re, "be a name") }, nil},
+		{"does not match", func(v *Validator) { v.Matches("name", "In Review", re, "be a name") }, []string{"name:" + CodeInvalidFormat}},
+		{
+			"several fields",
+			func(v *Validator) {
+				v.Required("title", "")
+				v.MaxLength("description", "abcd", 3)
+				v.OneOf("priority", "x", []string{"low"})
+			},
+			[]string{"title:" + CodeRequired, "description:" + CodeTooLong, "priority:" + CodeInvalidEnum},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+			tt.check(&v)
+
+			err := v.Err()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Err() = %v, want Errors", err)
+			}
+			if got := codes(errs); !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorMessages(t *testing.T) {
+	var v Validator
+	v.MaxLength("title", "abcd", 3)
+	v.OneOf("priority", "x", []string{"low", "high"})
+
+	want := "validation failed: title must be at most 3 characters; priority must be one of: low, high"
+	if got := v.Err().Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	if !v.Failed("title") || v.Failed("status") {
+		t.Errorf("Failed() does not track fields with errors")
+	}
+}